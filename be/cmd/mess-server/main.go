@@ -8,8 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jostrzol/mess/configs/serverconfig"
 	"github.com/jostrzol/mess/pkg/logger"
+	_ "github.com/jostrzol/mess/pkg/server/adapter/bolt"
 	_ "github.com/jostrzol/mess/pkg/server/adapter/handler"
-	_ "github.com/jostrzol/mess/pkg/server/adapter/inmem"
 	"github.com/jostrzol/mess/pkg/server/ioc"
 	"go.uber.org/zap"
 )
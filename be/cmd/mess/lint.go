@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/jostrzol/mess/pkg/rules"
+)
+
+func lint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	var rulesFilename = flags.String("rules", "", "path to a rules file")
+	flags.Parse(args)
+
+	if *rulesFilename == "" {
+		fmt.Printf("error: no rules file\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	src, err := os.ReadFile(*rulesFilename)
+	if err != nil {
+		runError("reading game rules: %s", err)
+	}
+	file := &rules.File{Src: src, Filename: *rulesFilename}
+
+	diags := rules.Lint(file)
+	files := map[string]*hcl.File{file.Filename: {Bytes: file.Src}}
+	writer := hcl.NewDiagnosticTextWriter(os.Stdout, files, 80, false)
+	if err = writer.WriteDiagnostics(diags); err != nil {
+		runError("writing diagnostics: %s", err)
+	}
+
+	if diags.HasErrors() {
+		os.Exit(2)
+	}
+	fmt.Println("No problems found")
+}
@@ -5,8 +5,14 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/jostrzol/mess/pkg/cmd"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/engine"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
 	"github.com/jostrzol/mess/pkg/rules"
 )
 
@@ -24,19 +30,110 @@ func runError(format string, a ...any) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "perft" {
+		perft(os.Args[2:])
+		return
+	} else if len(os.Args) > 1 && os.Args[1] == "lint" {
+		lint(os.Args[2:])
+		return
+	} else if len(os.Args) > 1 && os.Args[1] == "test" {
+		runTests(os.Args[2:])
+		return
+	} else if len(os.Args) > 1 && os.Args[1] == "replay" {
+		replay(os.Args[2:])
+		return
+	}
+
 	var rulesFilename = flag.String("rules", "", "path to a rules file")
+	var loadFilename = flag.String("load", "", "path to a game file to continue")
+	var saveFilename = flag.String("save", "", "path to a game file to record the game to")
+	var computerColors = flag.String("computer", "", "comma-separated colors played by the computer")
+	var engineName = flag.String("engine", "minimax", "computer engine: minimax or mcts")
+	var depth = flag.Int("depth", 2, "search depth of the minimax engine")
+	var iterations = flag.Int("iterations", 200, "number of iterations of the mcts engine")
+	var setup = flag.String("position", "", "position to start the game from, in the setup notation")
 	flag.Parse()
 
 	if *rulesFilename == "" {
 		cmdError("no rules file")
+	} else if *setup != "" && *loadFilename != "" {
+		cmdError("cannot both load a game file and start from a position")
+	}
+
+	var computer engine.Engine
+	switch *engineName {
+	case "minimax":
+		computer = &engine.Minimax{Depth: *depth}
+	case "mcts":
+		computer = &engine.MCTS{Iterations: *iterations, RolloutDepth: *depth}
+	default:
+		cmdError("unknown engine %q", *engineName)
+	}
+	computers := make(map[color.Color]engine.Engine)
+	if *computerColors != "" {
+		for _, colorStr := range strings.Split(*computerColors, ",") {
+			playerColor, err := color.ColorString(colorStr)
+			if err != nil {
+				cmdError("parsing computer color: %s", err)
+			}
+			computers[playerColor] = computer
+		}
 	}
 
-	game, err := rules.DecodeRulesFromOs(*rulesFilename, true)
+	rulesFile, err := readRulesFile(*rulesFilename)
 	if err != nil {
-		runError("loading game rules: %s", err)
+		runError("reading game rules: %s", err)
 	}
 
-	winner, err := cmd.Run(game, os.Stdin, os.Stdout)
+	var game *mess.Game
+	var routes []mess.Route
+	if *loadFilename != "" {
+		var gameFile *notation.GameFile
+		gameFile, err = loadGameFile(*loadFilename, rulesFile)
+		if err != nil {
+			runError("loading game file: %s", err)
+		}
+		*setup, _ = gameFile.Header(notation.HeaderSetup)
+		game, err = gameFile.NewGame(rulesFile)
+		if err != nil {
+			runError("loading game rules: %s", err)
+		}
+		routes, err = gameFile.Replay(game)
+		if err != nil {
+			runError("loading game file: %s", err)
+		}
+	} else if *setup != "" {
+		game, err = rules.DecodeRulesWithSetup(rulesFile, *setup)
+		if err != nil {
+			runError("loading game rules: %s", err)
+		}
+	} else {
+		game, err = rules.DecodeRules(rulesFile, true)
+		if err != nil {
+			runError("loading game rules: %s", err)
+		}
+	}
+
+	var onTurn func(mess.Route) error
+	if *saveFilename != "" {
+		onTurn = func(route mess.Route) error {
+			routes = append(routes, route)
+			gameFile := notation.NewGameFile(rulesFile, routes)
+			if *setup != "" {
+				gameFile.SetHeader(notation.HeaderSetup, *setup)
+			}
+			err := os.WriteFile(*saveFilename, []byte(gameFile.String()), 0644)
+			if err != nil {
+				return fmt.Errorf("saving game file: %w", err)
+			}
+			return nil
+		}
+	}
+
+	winner, err := cmd.Run(game, os.Stdin, os.Stdout, cmd.Config{
+		OnTurn:    onTurn,
+		Computers: computers,
+	})
 	if errors.Is(err, cmd.ErrEOT) {
 		os.Exit(3)
 	} else if err != nil {
@@ -52,3 +149,27 @@ func main() {
 		fmt.Printf("Winner is %v!\n", winner)
 	}
 }
+
+func readRulesFile(filename string) (*rules.File, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &rules.File{Src: src, Filename: filepath.Base(filename)}, nil
+}
+
+func loadGameFile(filename string, rulesFile *rules.File) (*notation.GameFile, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	gameFile, err := notation.ParseGameFile(src)
+	if err != nil {
+		return nil, err
+	}
+	err = gameFile.CheckRules(rulesFile)
+	if err != nil {
+		return nil, err
+	}
+	return gameFile, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/rules"
+)
+
+func perft(args []string) {
+	flags := flag.NewFlagSet("perft", flag.ExitOnError)
+	var rulesFilename = flags.String("rules", "", "path to a rules file")
+	var depth = flags.Int("depth", 1, "depth of the game tree to walk")
+	var divide = flags.Bool("divide", false, "break the count down per first route")
+	flags.Parse(args)
+
+	if *rulesFilename == "" {
+		fmt.Printf("error: no rules file\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	game, err := rules.DecodeRulesFromOs(*rulesFilename, true)
+	if err != nil {
+		runError("loading game rules: %s", err)
+	}
+
+	if !*divide {
+		nodes, err := game.Perft(*depth)
+		if err != nil {
+			runError("running perft: %s", err)
+		}
+		fmt.Printf("Nodes: %d\n", nodes)
+		return
+	}
+
+	divisions, err := game.PerftDivide(*depth)
+	if err != nil {
+		runError("running perft: %s", err)
+	}
+	total := 0
+	for _, division := range divisions {
+		fmt.Printf("%s: %d\n", notation.FormatRoute(division.Route), division.Nodes)
+		total += division.Nodes
+	}
+	fmt.Println()
+	fmt.Printf("Nodes: %d\n", total)
+}
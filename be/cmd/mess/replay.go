@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jostrzol/mess/pkg/cmd"
+)
+
+func replay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	var rulesFilename = flags.String("rules", "", "path to a rules file")
+	var loadFilename = flags.String("load", "", "path to a game file to replay")
+	flags.Parse(args)
+
+	if *rulesFilename == "" || *loadFilename == "" {
+		fmt.Printf("error: both rules and game files are required\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	rulesFile, err := readRulesFile(*rulesFilename)
+	if err != nil {
+		runError("reading game rules: %s", err)
+	}
+	gameFile, err := loadGameFile(*loadFilename, rulesFile)
+	if err != nil {
+		runError("loading game file: %s", err)
+	}
+	game, err := gameFile.NewGame(rulesFile)
+	if err != nil {
+		runError("loading game rules: %s", err)
+	}
+	routes, err := gameFile.Replay(game)
+	if err != nil {
+		runError("loading game file: %s", err)
+	}
+
+	err = cmd.Replay(game, routes, os.Stdin, os.Stdout)
+	if err != nil {
+		runError("replaying game: %s", err)
+	}
+}
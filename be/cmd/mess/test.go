@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/rules/rulestest"
+)
+
+func runTests(args []string) {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	var rulesFilename = flags.String("rules", "", "path to a rules file")
+	var testsFilename = flags.String("tests", "", "path to a tests file (default: <rules>_test.hcl)")
+	flags.Parse(args)
+
+	if *rulesFilename == "" {
+		fmt.Printf("error: no rules file\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+	if *testsFilename == "" {
+		*testsFilename = rulestest.TestsFilename(*rulesFilename)
+	}
+
+	src, err := os.ReadFile(*rulesFilename)
+	if err != nil {
+		runError("reading game rules: %s", err)
+	}
+	file := &rules.File{Src: src, Filename: *rulesFilename}
+
+	tests, err := rulestest.DecodeTestsFromOs(*testsFilename)
+	if err != nil {
+		runError("loading tests: %s", err)
+	}
+
+	failed := 0
+	for _, test := range tests {
+		errs := test.Run(file)
+		if len(errs) == 0 {
+			fmt.Printf("PASS %s\n", test.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL %s\n", test.Name)
+		for _, err := range errs {
+			fmt.Printf("    %s\n", err)
+		}
+	}
+
+	fmt.Println()
+	if failed != 0 {
+		fmt.Printf("%d of %d tests failed\n", failed, len(tests))
+		os.Exit(2)
+	}
+	fmt.Printf("All %d tests passed\n", len(tests))
+}
@@ -19,6 +19,8 @@ type Config struct {
 	AssetsCacheMaxAge  int           `mapstructure:"assets_cache_max_age"`
 	HeartbeatPeriod    time.Duration `mapstructure:"heartbeat_period"`
 	MaxWebsocketErrors int           `mapstructure:"max_websocket_errors"`
+	DatabasePath       string        `mapstructure:"database_path"`
+	ComputerDepth      int           `mapstructure:"computer_depth"`
 }
 
 func setDefaults(v *viper.Viper) {
@@ -29,6 +31,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("assets_cache_max_age", 600)
 	v.SetDefault("heartbeat_period", time.Second*5)
 	v.SetDefault("max_websocket_errors", 5)
+	v.SetDefault("database_path", "./mess.db")
+	v.SetDefault("computer_depth", 2)
 }
 
 func generateSessionSecret() string {
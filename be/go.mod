@@ -18,6 +18,7 @@ require (
 	github.com/spf13/viper v1.17.0
 	github.com/stretchr/testify v1.8.4
 	github.com/zclconf/go-cty v1.12.1
+	go.etcd.io/bbolt v1.3.8
 	go.uber.org/zap v1.25.0
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9
 )
@@ -42,26 +42,36 @@ func (b Board[T]) String() string {
 }
 
 func (b Board[T]) PrettyString(itemFormatter func(T) rune) string {
+	return b.PrettySquaresString(Rectangular, func(_ Square, item T) rune {
+		return itemFormatter(item)
+	})
+}
+
+// PrettySquaresString is like PrettyString, but the formatter also receives
+// the square of the item and the rows are laid out according to the topology.
+func (b Board[T]) PrettySquaresString(topology Topology, itemFormatter func(Square, T) rune) string {
 	var builder strings.Builder
-	b.printBar(&builder)
+	b.printBar(&builder, topology)
 	builder.WriteRune('\n')
 	for y := len(b) - 1; y >= 0; y-- {
 		row := b[y]
-		b.printRow(&builder, y+1, row, itemFormatter)
+		indent := b.rowIndent(topology, y)
+		b.printRow(&builder, y+1, indent, row, itemFormatter)
+		fmt.Fprintf(&builder, "%*s", b.rowIndent(topology, 0)-indent, "")
 		builder.WriteRune('\n')
-		b.printBar(&builder)
+		b.printBar(&builder, topology)
 		builder.WriteRune('\n')
 	}
-	b.printFileHeader(&builder)
+	b.printFileHeader(&builder, b.rowIndent(topology, 0))
 	builder.WriteRune('\n')
-	b.printBar(&builder)
+	b.printBar(&builder, topology)
 	return builder.String()
 }
 
-func (b Board[T]) printBar(w io.ByteWriter) {
+func (b Board[T]) printBar(w io.ByteWriter, topology Topology) {
 	width, _ := b.Size()
-	widthWithHeader := width + 1
-	for i := 0; i < widthWithHeader*3+1; i++ {
+	length := 1 + b.rankHeaderWidth() + 1 + width*(b.cellWidth()+1) + b.rowIndent(topology, 0)
+	for i := 0; i < length; i++ {
 		err := w.WriteByte('-')
 		if err != nil {
 			panic(err)
@@ -69,29 +79,51 @@ func (b Board[T]) printBar(w io.ByteWriter) {
 	}
 }
 
-func (b Board[T]) printRow(w io.Writer, rank int, row []T, itemFormatter func(T) rune) {
-	fmt.Fprintf(w, "|%2d|", rank)
-	for _, item := range row {
-		sign := itemFormatter(item)
+func (b Board[T]) printRow(w io.Writer, rank int, indent int, row []T, itemFormatter func(Square, T) rune) {
+	fmt.Fprintf(w, "|%*d|%*s", b.rankHeaderWidth(), rank, indent, "")
+	for x, item := range row {
+		sign := itemFormatter(SquareFromCoords(x, rank-1), item)
 		bytes := make([]byte, utf8.RuneLen(sign))
 		if n := utf8.EncodeRune(bytes, sign); n != len(bytes) {
 			panic(fmt.Errorf("printing board row: expected to write %d bytes but wrote %d", len(bytes), n))
 		}
-		fmt.Fprintf(w, "%-2s|", bytes)
+		fmt.Fprintf(w, "%-*s|", b.cellWidth(), bytes)
 	}
 }
 
-func (b Board[T]) printFileHeader(w io.Writer) {
-	_, err := w.Write([]byte("|  |"))
+// rowIndent shifts each rank of a hexagonal board by half a cell to the right
+// relative to the one above it.
+func (b Board[T]) rowIndent(topology Topology, y int) int {
+	if topology != Hexagonal {
+		return 0
+	}
+	_, height := b.Size()
+	return (height - 1 - y) * (b.cellWidth() + 1) / 2
+}
+
+func (b Board[T]) printFileHeader(w io.Writer, indent int) {
+	_, err := fmt.Fprintf(w, "|%*s|%*s", b.rankHeaderWidth(), "", indent, "")
 	if err != nil {
 		return
 	}
 	width, _ := b.Size()
 	for i := 1; i <= width; i++ {
-		fmt.Fprintf(w, "%-2s|", fileString(i))
+		fmt.Fprintf(w, "%-*s|", b.cellWidth(), fileString(i))
 	}
 }
 
+// cellWidth is wide enough to fit the name of the last file.
+func (b Board[T]) cellWidth() int {
+	width, _ := b.Size()
+	return max(2, len(fileString(width)))
+}
+
+// rankHeaderWidth is wide enough to fit the number of the last rank.
+func (b Board[T]) rankHeaderWidth() int {
+	_, height := b.Size()
+	return max(2, len(fmt.Sprint(height)))
+}
+
 func (b Board[T]) Size() (int, int) {
 	row := b[0]
 	return len(row), len(b)
@@ -138,3 +170,10 @@ func (b Board[T]) AllItems() []T {
 	}
 	return items
 }
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
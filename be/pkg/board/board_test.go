@@ -2,6 +2,7 @@ package board_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/jostrzol/mess/pkg/board"
@@ -133,6 +134,27 @@ func (s *BoardSuite) TestAllPieces() {
 	s.ElementsMatch(items, []int{1, 2})
 }
 
+func TestPrettyStringLarge(t *testing.T) {
+	brd, err := board.NewBoard[int](28, 10)
+	assert.NoError(t, err)
+	_, err = brd.Place(1, boardtest.NewSquare("AB10"))
+	assert.NoError(t, err)
+
+	lines := strings.Split(brd.PrettyString(func(i int) rune {
+		if i == 0 {
+			return ' '
+		}
+		return 'X'
+	}), "\n")
+
+	assert.True(t, strings.HasSuffix(lines[1], "|X |"))
+	assert.True(t, strings.HasPrefix(lines[1], "|10|"))
+	assert.True(t, strings.HasSuffix(lines[len(lines)-2], "|Z |AA|AB|"))
+	for _, line := range lines {
+		assert.Len(t, line, len(lines[0]))
+	}
+}
+
 func TestBoardSuite(t *testing.T) {
 	suite.Run(t, new(BoardSuite))
 }
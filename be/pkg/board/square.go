@@ -12,19 +12,33 @@ type Square struct {
 	Rank int
 }
 
+// NewSquare parses a square written as its file letters followed by its rank
+// number, e.g. "A1", "J10" or "AB12". Files past "Z" continue with "AA", "AB",
+// and so on. Letters are case-insensitive.
 func NewSquare(text string) (Square, error) {
 	var zero Square
-	if len(text) != 2 {
-		return zero, errors.New("malformed position: expected 2 characters")
+	text = strings.ToUpper(text)
+
+	nLetters := strings.IndexFunc(text, func(r rune) bool { return r < 'A' || r > 'Z' })
+	switch nLetters {
+	case -1:
+		return zero, errors.New("malformed position: missing rank")
+	case 0:
+		return zero, errors.New("malformed position: missing file")
 	}
 
-	fileRune := int(strings.ToUpper(text)[0])
-	if fileRune < 'A' || fileRune > 'Z' {
-		return zero, fmt.Errorf("expected letter, not %q", fileRune)
+	file := 0
+	for _, letter := range text[:nLetters] {
+		file = file*26 + int(letter-'A') + 1
 	}
-	file := fileRune - 'A' + 1
 
-	rank, err := strconv.Atoi(string(text[1]))
+	rankStr := text[nLetters:]
+	for _, digit := range rankStr {
+		if digit < '0' || digit > '9' {
+			return zero, fmt.Errorf("malformed position: expected digit, not %q", digit)
+		}
+	}
+	rank, err := strconv.Atoi(rankStr)
 	if err != nil {
 		return zero, fmt.Errorf("parsing rank: %v", err)
 	}
@@ -39,8 +53,16 @@ func (s Square) String() string {
 	return fmt.Sprintf("%s%d", fileString(s.File), s.Rank)
 }
 
+// fileString converts a file number to letters: 1 -> "A", 26 -> "Z",
+// 27 -> "AA" and so on.
 func fileString(file int) string {
-	return string(byte(file-1) + 'A')
+	var result []byte
+	for file > 0 {
+		file--
+		result = append([]byte{byte(file%26) + 'A'}, result...)
+		file /= 26
+	}
+	return string(result)
 }
 
 type Offset struct {
@@ -21,6 +21,13 @@ func TestNewSquare(t *testing.T) {
 		{"H6", 8, 6},
 		{"Z9", 26, 9},
 		{"a1", 1, 1},
+		{"A10", 1, 10},
+		{"J10", 10, 10},
+		{"P16", 16, 16},
+		{"AA1", 27, 1},
+		{"ab12", 28, 12},
+		{"AZ100", 52, 100},
+		{"BA3", 53, 3},
 	}
 	for _, tt := range tests {
 		t.Run(tt.str, func(t *testing.T) {
@@ -33,7 +40,7 @@ func TestNewSquare(t *testing.T) {
 }
 
 func TestNewSquareMalformed(t *testing.T) {
-	tests := []string{"A10", "Ż1", "A", "1", "AB1", "hello", "-", " ", "", " A1", "A1 ", " A1 "}
+	tests := []string{"Ż1", "A", "1", "1A", "A1B", "A0", "A-1", "hello", "-", " ", "", " A1", "A1 ", " A1 "}
 	for _, str := range tests {
 		t.Run(str, func(t *testing.T) {
 			_, err := board.NewSquare(str)
@@ -54,6 +61,10 @@ func TestString(t *testing.T) {
 		{"H6", "H6"},
 		{"Z9", "Z9"},
 		{"a1", "A1"},
+		{"A10", "A10"},
+		{"aa1", "AA1"},
+		{"AZ100", "AZ100"},
+		{"BA3", "BA3"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
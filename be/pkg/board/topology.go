@@ -0,0 +1,57 @@
+package board
+
+//go:generate enumer -type=Topology -transform=snake
+type Topology int
+
+const (
+	// Rectangular boards are bounded on all sides.
+	Rectangular Topology = iota
+	// Cylindrical boards wrap around the files: the file after the last one
+	// is the first one.
+	Cylindrical
+	// Toroidal boards wrap around both the files and the ranks.
+	Toroidal
+	// Hexagonal boards consist of hexagons in axial coordinates: each rank is
+	// shifted by half a hexagon to the right relative to the one above it.
+	// That way a hexagon neighbours the two on its sides, the two above it
+	// (offset by [0, 1] and [1, 1]) and the two below it (offset by [0, -1]
+	// and [-1, -1]).
+	Hexagonal
+)
+
+// Offset returns the square offset by the given offset on a board of the given
+// size. The result might still lie outside the board.
+func (t Topology) Offset(square Square, offset Offset, width int, height int) Square {
+	return t.Wrap(square.Offset(offset), width, height)
+}
+
+// Wrap maps a square outside of the wrapped edges of a board of the given size
+// back onto the board.
+func (t Topology) Wrap(square Square, width int, height int) Square {
+	x, y := square.ToCoords()
+	switch t {
+	case Cylindrical:
+		x = mod(x, width)
+	case Toroidal:
+		x, y = mod(x, width), mod(y, height)
+	}
+	return SquareFromCoords(x, y)
+}
+
+// Directions returns the offsets to the neighbours of a square.
+func (t Topology) Directions() []Offset {
+	if t == Hexagonal {
+		return []Offset{
+			{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1},
+			{X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: -1},
+		}
+	}
+	return []Offset{
+		{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: -1, Y: 1},
+		{X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: -1}, {X: 1, Y: -1},
+	}
+}
+
+func mod(a int, n int) int {
+	return (a%n + n) % n
+}
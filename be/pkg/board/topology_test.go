@@ -0,0 +1,77 @@
+package board_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/board/boardtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologyOffset(t *testing.T) {
+	tests := []struct {
+		topology board.Topology
+		input    string
+		x        int
+		y        int
+		expected string
+	}{
+		{board.Rectangular, "H1", 1, 0, "I1"},
+		{board.Rectangular, "H8", 0, 1, "H9"},
+		{board.Cylindrical, "A1", -1, 0, "H1"},
+		{board.Cylindrical, "H4", 3, 1, "C5"},
+		{board.Cylindrical, "H8", 0, 1, "H9"},
+		{board.Toroidal, "A1", -1, -1, "H8"},
+		{board.Toroidal, "H8", 1, 1, "A1"},
+		{board.Toroidal, "D4", 16, -8, "D4"},
+		{board.Hexagonal, "H1", 1, 0, "I1"},
+		{board.Hexagonal, "B2", 1, 1, "C3"},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s:%s->%s", tt.topology, tt.input, tt.expected)
+		t.Run(name, func(t *testing.T) {
+			square := boardtest.NewSquare(tt.input)
+
+			result := tt.topology.Offset(square, board.Offset{X: tt.x, Y: tt.y}, 8, 8)
+
+			assert.Equal(t, tt.expected, result.String())
+		})
+	}
+}
+
+func TestTopologyDirections(t *testing.T) {
+	assert.Len(t, board.Rectangular.Directions(), 8)
+	assert.Len(t, board.Toroidal.Directions(), 8)
+	hexDirections := board.Hexagonal.Directions()
+	assert.Len(t, hexDirections, 6)
+	assert.NotContains(t, hexDirections, board.Offset{X: -1, Y: 1})
+	assert.NotContains(t, hexDirections, board.Offset{X: 1, Y: -1})
+}
+
+func TestTopologyString(t *testing.T) {
+	for _, topology := range board.TopologyValues() {
+		parsed, err := board.TopologyString(topology.String())
+		assert.NoError(t, err)
+		assert.Equal(t, topology, parsed)
+	}
+	_, err := board.TopologyString("spherical")
+	assert.Error(t, err)
+}
+
+func TestPrettyStringHexagonal(t *testing.T) {
+	brd, err := board.NewBoard[int](3, 3)
+	assert.NoError(t, err)
+
+	lines := strings.Split(brd.PrettySquaresString(board.Hexagonal, func(board.Square, int) rune {
+		return 'X'
+	}), "\n")
+
+	assert.True(t, strings.HasPrefix(lines[1], "| 3|X |"))
+	assert.True(t, strings.HasPrefix(lines[3], "| 2| X |"))
+	assert.True(t, strings.HasPrefix(lines[5], "| 1|   X |"))
+	for _, line := range lines {
+		assert.Len(t, line, len(lines[0]))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
 )
 
 const barLength = 80
@@ -17,12 +18,14 @@ var ErrEOT = fmt.Errorf("EOT")
 type interactor struct {
 	scanner *bufio.Scanner
 	game    *mess.Game
+	config  Config
 }
 
-func newInteractor(game *mess.Game, scanner *bufio.Scanner) *interactor {
+func newInteractor(game *mess.Game, scanner *bufio.Scanner, config Config) *interactor {
 	return &interactor{
 		scanner: scanner,
 		game:    game,
+		config:  config,
 	}
 }
 
@@ -31,12 +34,7 @@ func (t *interactor) Run() (*mess.Player, error) {
 
 	t.printState()
 	for !resolution.DidEnd {
-		optionTree, err := t.game.TurnOptions()
-		if err != nil {
-			return nil, err
-		}
-
-		options, err := t.selectOptions(optionTree)
+		options, err := t.chooseOptions()
 		if errors.Is(err, ErrCancel) {
 			t.printMessage("Move cancelled")
 			t.printState()
@@ -54,6 +52,12 @@ func (t *interactor) Run() (*mess.Player, error) {
 		if err != nil {
 			return nil, fmt.Errorf("executing turn action: %v", err)
 		}
+		if t.config.OnTurn != nil {
+			err = t.config.OnTurn(options)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		t.printState()
 
@@ -62,6 +66,24 @@ func (t *interactor) Run() (*mess.Player, error) {
 	return resolution.Winner, nil
 }
 
+func (t *interactor) chooseOptions() ([]mess.Option, error) {
+	computer, ok := t.config.Computers[t.game.CurrentPlayer().Color()]
+	if ok {
+		route, err := computer.BestRoute(t.game)
+		if err != nil {
+			return nil, fmt.Errorf("choosing computer's turn: %w", err)
+		}
+		t.printMessage("Computer plays %s", notation.FormatRoute(route))
+		return route, nil
+	}
+
+	optionTree, err := t.game.TurnOptions()
+	if err != nil {
+		return nil, err
+	}
+	return t.selectOptions(optionTree)
+}
+
 func (t *interactor) printState() {
 	fmt.Println(t.game.PrettyString())
 }
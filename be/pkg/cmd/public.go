@@ -4,12 +4,22 @@ import (
 	"bufio"
 	"io"
 
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/engine"
 	"github.com/jostrzol/mess/pkg/mess"
 )
 
-func Run(game *mess.Game, in io.Reader, _ io.Writer) (*mess.Player, error) {
+type Config struct {
+	// OnTurn, if not nil, is called with the route chosen in each played turn.
+	OnTurn func(mess.Route) error
+	// Computers maps colors of the players controlled by the computer to
+	// the engines choosing their turns.
+	Computers map[color.Color]engine.Engine
+}
+
+func Run(game *mess.Game, in io.Reader, _ io.Writer, config Config) (*mess.Player, error) {
 	scanner := bufio.NewScanner(in)
 	// TODO: handle out
-	i := newInteractor(game, scanner)
+	i := newInteractor(game, scanner, config)
 	return i.Run()
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
+)
+
+const replayHelp = `Commands:
+  n, next (or empty)  go to the next turn
+  p, prev             go to the previous turn
+  f, first            go to the start of the game
+  l, last             go to the end of the game
+  <number>            go to the position after the given number of turns
+  q, quit             stop replaying`
+
+// Replay lets the user step through the routes played in the game, starting
+// from the beginning. The game must be in the position after all the routes
+// were played, e.g. replayed with notation.GameFile.Replay.
+func Replay(game *mess.Game, routes []mess.Route, in io.Reader, _ io.Writer) error {
+	r := &replayer{
+		interactor: newInteractor(game, bufio.NewScanner(in), Config{}),
+		routes:     routes,
+		played:     len(routes),
+	}
+	err := r.goTo(0)
+	if err != nil {
+		return err
+	}
+	return r.Run()
+}
+
+type replayer struct {
+	*interactor
+	routes []mess.Route
+	// played is the number of routes played so far.
+	played int
+}
+
+func (r *replayer) Run() error {
+	fmt.Println(replayHelp)
+	r.printReplayState()
+	for {
+		text, err := r.scan()
+		if errors.Is(err, ErrCancel) {
+			text = "next"
+		} else if errors.Is(err, ErrEOT) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		target := r.played
+		switch strings.TrimSpace(text) {
+		case "n", "next":
+			target++
+		case "p", "prev":
+			target--
+		case "f", "first":
+			target = 0
+		case "l", "last":
+			target = len(r.routes)
+		case "q", "quit":
+			return nil
+		default:
+			target, err = strconv.Atoi(text)
+			if err != nil {
+				r.printMessage("-> Error: unknown command %q!", text)
+				fmt.Println(replayHelp)
+				continue
+			}
+		}
+
+		if target < 0 || target > len(r.routes) {
+			r.printMessage("-> Error: no position after %d turns (the game has %d)!", target, len(r.routes))
+			continue
+		}
+		err = r.goTo(target)
+		if err != nil {
+			return err
+		}
+		r.printReplayState()
+	}
+}
+
+func (r *replayer) goTo(target int) error {
+	for r.played > target {
+		err := r.game.RevertTurn()
+		if err != nil {
+			return fmt.Errorf("reverting turn %d: %w", r.played, err)
+		}
+		r.played--
+	}
+	for r.played < target {
+		err := r.game.PlayTurn(r.routes[r.played])
+		if err != nil {
+			return fmt.Errorf("playing turn %d: %w", r.played+1, err)
+		}
+		r.played++
+	}
+	return nil
+}
+
+func (r *replayer) printReplayState() {
+	r.printState()
+	if r.played == 0 {
+		r.printMessage("Start of the game (%d turns)", len(r.routes))
+	} else {
+		r.printMessage("After turn %d of %d: %s",
+			r.played, len(r.routes), notation.FormatRoute(r.routes[r.played-1]))
+	}
+}
@@ -6,4 +6,10 @@ type Color int
 const (
 	White Color = iota
 	Black
+	Red
+	Green
+	Blue
+	Yellow
+	Orange
+	Purple
 )
@@ -6,9 +6,9 @@ import (
 	"fmt"
 )
 
-const _ColorName = "whiteblack"
+const _ColorName = "whiteblackredgreenblueyelloworangepurple"
 
-var _ColorIndex = [...]uint8{0, 5, 10}
+var _ColorIndex = [...]uint8{0, 5, 10, 13, 18, 22, 28, 34, 40}
 
 func (i Color) String() string {
 	if i < 0 || i >= Color(len(_ColorIndex)-1) {
@@ -17,11 +17,17 @@ func (i Color) String() string {
 	return _ColorName[_ColorIndex[i]:_ColorIndex[i+1]]
 }
 
-var _ColorValues = []Color{0, 1}
+var _ColorValues = []Color{0, 1, 2, 3, 4, 5, 6, 7}
 
 var _ColorNameToValueMap = map[string]Color{
-	_ColorName[0:5]:  0,
-	_ColorName[5:10]: 1,
+	_ColorName[0:5]:   0,
+	_ColorName[5:10]:  1,
+	_ColorName[10:13]: 2,
+	_ColorName[13:18]: 3,
+	_ColorName[18:22]: 4,
+	_ColorName[22:28]: 5,
+	_ColorName[28:34]: 6,
+	_ColorName[34:40]: 7,
 }
 
 // ColorString retrieves an enum value from the enum constants string name.
@@ -0,0 +1,55 @@
+// Package engine implements computer players, which choose turns by searching
+// the option trees of a game.
+package engine
+
+import (
+	"fmt"
+
+	"github.com/jostrzol/mess/pkg/mess"
+)
+
+// Engine chooses a route for the current player of the game. The game is
+// used for the search, but is left in the same state afterwards.
+type Engine interface {
+	BestRoute(game *mess.Game) (mess.Route, error)
+}
+
+// Evaluator estimates how favourable the game state is for the given player.
+// The greater the score, the better.
+type Evaluator interface {
+	Evaluate(game *mess.Game, player *mess.Player) float64
+}
+
+type EvaluatorFunc func(game *mess.Game, player *mess.Player) float64
+
+func (f EvaluatorFunc) Evaluate(game *mess.Game, player *mess.Player) float64 {
+	return f(game, player)
+}
+
+// routes returns all the routes available to the current player.
+func routes(game *mess.Game) ([]mess.Route, error) {
+	optionTree, err := game.TurnOptions()
+	if err != nil {
+		return nil, fmt.Errorf("generating turn options: %w", err)
+	}
+	return optionTree.AllRoutes(), nil
+}
+
+// play plays the route, undoing the partially played turn on failure.
+func play(game *mess.Game, route mess.Route) error {
+	err := game.PlayTurn(route)
+	if err != nil {
+		game.UndoTurn()
+		return err
+	}
+	return nil
+}
+
+func revert(game *mess.Game) {
+	err := game.RevertTurn()
+	if err != nil {
+		panic(fmt.Errorf("reverting a played turn: %w", err))
+	}
+}
+
+var ErrNoRoutes = fmt.Errorf("no routes available")
@@ -0,0 +1,77 @@
+package engine_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/engine"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dobutsuShogiRulesFile = "../../rules/dobutsu_shogi.hcl"
+
+// lionInDanger returns a dobutsu shogi game, in which white can win in one
+// turn by capturing the black lion: B3-B4.
+func lionInDanger(t *testing.T) *mess.Game {
+	t.Helper()
+	game, err := rules.DecodeRulesFromOs(dobutsuShogiRulesFile, true)
+	require.NoError(t, err)
+	for _, turn := range []string{"*/B2-B3", "*/A4-A3"} {
+		route, err := notation.ParseRoute(game.State, turn)
+		require.NoError(t, err)
+		require.NoError(t, game.PlayTurn(route))
+	}
+	return game
+}
+
+func TestMinimaxFindsWin(t *testing.T) {
+	game := lionInDanger(t)
+	minimax := &engine.Minimax{Depth: 1}
+
+	route, err := minimax.BestRoute(game)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "*/B3-B4", notation.FormatRoute(route))
+	assert.Equal(t, 2, game.TurnNumber())
+}
+
+func TestMCTSFindsWin(t *testing.T) {
+	game := lionInDanger(t)
+	mcts := &engine.MCTS{
+		Iterations:   120,
+		RolloutDepth: 0,
+		Rand:         rand.New(rand.NewSource(1)),
+	}
+
+	route, err := mcts.BestRoute(game)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "*/B3-B4", notation.FormatRoute(route))
+	assert.Equal(t, 2, game.TurnNumber())
+}
+
+func TestMaterialMobility(t *testing.T) {
+	game := lionInDanger(t)
+	evaluator := engine.MaterialMobility{MaterialWeight: 1, MobilityWeight: 0}
+
+	// white has captured a chick
+	assert.Equal(t, 1., evaluator.Evaluate(game, game.Player(color.White)))
+	assert.Equal(t, -1., evaluator.Evaluate(game, game.Player(color.Black)))
+}
+
+func TestDefaultEvaluatorWithoutRulesEvaluation(t *testing.T) {
+	game := lionInDanger(t)
+	white := game.Player(color.White)
+
+	_, ok := game.Evaluate(white)
+
+	assert.False(t, ok)
+	assert.Equal(t,
+		engine.DefaultMaterialMobility.Evaluate(game, white),
+		engine.DefaultEvaluator.Evaluate(game, white))
+}
@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"github.com/jostrzol/mess/pkg/mess"
+)
+
+// DefaultEvaluator uses the evaluation defined in the game rules (the
+// "evaluate" function) if available, or MaterialMobility otherwise.
+var DefaultEvaluator Evaluator = EvaluatorFunc(func(game *mess.Game, player *mess.Player) float64 {
+	if score, ok := game.Evaluate(player); ok {
+		return score
+	}
+	return DefaultMaterialMobility.Evaluate(game, player)
+})
+
+var DefaultMaterialMobility = MaterialMobility{
+	MaterialWeight: 1,
+	MobilityWeight: 0.1,
+}
+
+// MaterialMobility scores the difference between the player's and their
+// opponents' number of pieces (material) and number of moves (mobility).
+type MaterialMobility struct {
+	MaterialWeight float64
+	MobilityWeight float64
+}
+
+func (e MaterialMobility) Evaluate(game *mess.Game, player *mess.Player) float64 {
+	var material, mobility int
+	for _, other := range game.Players() {
+		sign := -1
+		if other == player {
+			sign = 1
+		}
+		material += sign * len(other.Pieces())
+		mobility += sign * len(other.Moves())
+	}
+	return e.MaterialWeight*float64(material) + e.MobilityWeight*float64(mobility)
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jostrzol/mess/pkg/mess"
+)
+
+// MCTS chooses routes with Monte Carlo tree search (UCT). Random playouts are
+// cut off after RolloutDepth turns, in which case the result is estimated
+// with the evaluator.
+type MCTS struct {
+	Iterations   int
+	RolloutDepth int
+	Exploration  float64
+	Evaluator    Evaluator
+	Rand         *rand.Rand
+}
+
+type mctsNode struct {
+	route    mess.Route
+	mover    *mess.Player
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []mess.Route
+	expanded bool
+	terminal bool
+	visits   float64
+	// wins is the sum of the playout results, from the mover's perspective.
+	wins float64
+}
+
+func (m *MCTS) BestRoute(game *mess.Game) (mess.Route, error) {
+	rootRoutes, err := routes(game)
+	if err != nil {
+		return nil, err
+	} else if len(rootRoutes) == 0 {
+		return nil, ErrNoRoutes
+	}
+	root := &mctsNode{untried: rootRoutes, expanded: true}
+
+	for i := 0; i < m.Iterations; i++ {
+		m.iterate(game, root)
+	}
+
+	var best *mctsNode
+	for _, child := range root.children {
+		if best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+	if best == nil {
+		return nil, ErrNoRoutes
+	}
+	return best.route, nil
+}
+
+func (m *MCTS) iterate(game *mess.Game, root *mctsNode) {
+	played := 0
+	defer func() {
+		for ; played > 0; played-- {
+			revert(game)
+		}
+	}()
+
+	// selection
+	node := root
+	for !node.terminal && len(node.untried) == 0 && len(node.children) > 0 {
+		node = m.selectChild(node)
+		if err := play(game, node.route); err != nil {
+			// should not happen, as the route has already been played once
+			return
+		}
+		played++
+	}
+
+	// expansion
+	if !node.terminal {
+		if !node.expanded {
+			node.untried, _ = routes(game)
+			node.expanded = true
+		}
+		for len(node.untried) > 0 {
+			i := m.rand().Intn(len(node.untried))
+			route := node.untried[i]
+			node.untried = append(node.untried[:i], node.untried[i+1:]...)
+
+			mover := game.CurrentPlayer()
+			if err := play(game, route); err != nil {
+				continue
+			}
+			played++
+			child := &mctsNode{route: route, mover: mover, parent: node}
+			child.terminal = game.Resolution().DidEnd
+			node.children = append(node.children, child)
+			node = child
+			break
+		}
+	}
+
+	// simulation
+	results, rolledOut := m.rollout(game)
+	played += rolledOut
+
+	// backpropagation
+	for ; node != nil; node = node.parent {
+		node.visits++
+		if node.mover != nil {
+			node.wins += results[node.mover]
+		}
+	}
+}
+
+func (m *MCTS) selectChild(node *mctsNode) *mctsNode {
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	for _, child := range node.children {
+		score := child.wins/child.visits +
+			m.exploration()*math.Sqrt(math.Log(node.visits)/child.visits)
+		if score > bestScore {
+			best = child
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// rollout plays random turns until the game ends or the depth is reached.
+// It returns the results of all players (in range [0, 1]) and the number
+// of played turns.
+func (m *MCTS) rollout(game *mess.Game) (map[*mess.Player]float64, int) {
+	played := 0
+	for {
+		resolution := game.Resolution()
+		if resolution.DidEnd {
+			results := make(map[*mess.Player]float64)
+			for _, player := range game.Players() {
+				switch resolution.Winner {
+				case nil:
+					results[player] = 0.5
+				case player:
+					results[player] = 1
+				default:
+					results[player] = 0
+				}
+			}
+			return results, played
+		}
+		if played >= m.RolloutDepth {
+			break
+		}
+
+		routes, err := routes(game)
+		if err != nil || len(routes) == 0 {
+			break
+		}
+		route := routes[m.rand().Intn(len(routes))]
+		if err := play(game, route); err != nil {
+			break
+		}
+		played++
+	}
+
+	evaluator := m.Evaluator
+	if evaluator == nil {
+		evaluator = DefaultEvaluator
+	}
+	results := make(map[*mess.Player]float64)
+	for _, player := range game.Players() {
+		results[player] = sigmoid(evaluator.Evaluate(game, player))
+	}
+	return results, played
+}
+
+func (m *MCTS) exploration() float64 {
+	if m.Exploration == 0 {
+		return math.Sqrt2
+	}
+	return m.Exploration
+}
+
+func (m *MCTS) rand() *rand.Rand {
+	if m.Rand == nil {
+		m.Rand = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return m.Rand
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
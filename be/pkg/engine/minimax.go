@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/jostrzol/mess/pkg/mess"
+)
+
+// winScore is the score of a won game. Wins found sooner score higher.
+const winScore = 1e9
+
+// Minimax searches the game tree to the given depth with alpha-beta pruning.
+// The player to move maximizes the score, while all the other players
+// minimize it.
+type Minimax struct {
+	Depth     int
+	Evaluator Evaluator
+}
+
+func (m *Minimax) BestRoute(game *mess.Game) (mess.Route, error) {
+	player := game.CurrentPlayer()
+	routes, err := routes(game)
+	if err != nil {
+		return nil, err
+	}
+
+	var best mess.Route
+	alpha := math.Inf(-1)
+	for _, route := range routes {
+		if err := play(game, route); err != nil {
+			continue
+		}
+		score := m.search(game, player, m.Depth-1, alpha, math.Inf(1))
+		revert(game)
+
+		if best == nil || score > alpha {
+			best = route
+			alpha = score
+		}
+	}
+	if best == nil {
+		return nil, ErrNoRoutes
+	}
+	return best, nil
+}
+
+func (m *Minimax) search(game *mess.Game, player *mess.Player, depth int, alpha, beta float64) float64 {
+	resolution := game.Resolution()
+	if resolution.DidEnd {
+		return resolutionScore(resolution, player, depth)
+	}
+	if depth <= 0 {
+		return m.evaluator().Evaluate(game, player)
+	}
+
+	routes, err := routes(game)
+	if err != nil || len(routes) == 0 {
+		return m.evaluator().Evaluate(game, player)
+	}
+
+	maximizing := game.CurrentPlayer() == player
+	result := math.Inf(1)
+	if maximizing {
+		result = math.Inf(-1)
+	}
+	for _, route := range routes {
+		if err := play(game, route); err != nil {
+			continue
+		}
+		score := m.search(game, player, depth-1, alpha, beta)
+		revert(game)
+
+		if maximizing {
+			result = math.Max(result, score)
+			alpha = math.Max(alpha, score)
+		} else {
+			result = math.Min(result, score)
+			beta = math.Min(beta, score)
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return result
+}
+
+func (m *Minimax) evaluator() Evaluator {
+	if m.Evaluator == nil {
+		return DefaultEvaluator
+	}
+	return m.Evaluator
+}
+
+func resolutionScore(resolution mess.Resolution, player *mess.Player, depth int) float64 {
+	switch resolution.Winner {
+	case nil:
+		return 0
+	case player:
+		return winScore + float64(depth)
+	default:
+		return -winScore - float64(depth)
+	}
+}
@@ -34,13 +34,54 @@ func (g *Game) Resolution() Resolution {
 	return g.controller.Resolution(g.State)
 }
 
+// Evaluate estimates how favourable the current state is for the given player.
+// Returns false if the controller does not implement Evaluator or could not
+// evaluate the state.
+func (g *Game) Evaluate(player *Player) (float64, bool) {
+	evaluator, ok := g.controller.(Evaluator)
+	if !ok {
+		return 0, false
+	}
+	return evaluator.Evaluate(g.State, player)
+}
+
 type Controller interface {
 	TurnChoice(state *State) (*Choice, error)
 	Turn(state *State, options []Option) error
 	Resolution(state *State) Resolution
 }
 
+// Evaluator can be optionally implemented by a Controller to estimate how
+// favourable a state is for a player. The greater the score, the better.
+type Evaluator interface {
+	Evaluate(state *State, player *Player) (float64, bool)
+}
+
 type Resolution struct {
 	DidEnd bool
 	Winner *Player
+	Reason EndReason
 }
+
+// EndReason explains why the game has ended.
+//
+//go:generate enumer -type=EndReason -transform=snake
+type EndReason int
+
+const (
+	NotEnded EndReason = iota
+	// RuleDefined is the reason of a game ended by the rules, which didn't
+	// specify any of the other reasons.
+	RuleDefined
+	Checkmate
+	Stalemate
+	Resignation
+	Agreement
+	Timeout
+	Aborted
+	// Repetition is the reason of a draw by repeating the same position.
+	Repetition
+	// NoProgress is the reason of a draw after many turns without progress,
+	// e.g. by the fifty-move rule in chess.
+	NoProgress
+)
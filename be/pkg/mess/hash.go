@@ -0,0 +1,160 @@
+package mess
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	brd "github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/event"
+)
+
+// Hash returns the Zobrist hash of the current position: the pieces on the
+// board with their properties, the captures held by each player, the game
+// variables and the player to move. Equal positions have equal hashes, also
+// across cloned boards and separate games using the same rules. The hash is
+// updated incrementally as the state changes.
+func (s *State) Hash() uint64 {
+	return s.zobrist.hash
+}
+
+type zobrist struct {
+	hash uint64
+	// inHand maps the captured pieces to the players holding them.
+	inHand map[*Piece]*Player
+	// handCounts counts the captured pieces of each type held by each player.
+	handCounts map[handKey]int
+}
+
+type handKey struct {
+	ty     *PieceType
+	holder color.Color
+}
+
+func newZobrist(board *PieceBoard, currentPlayer *Player) zobrist {
+	z := zobrist{
+		hash:       sideKey(currentPlayer.Color()),
+		inHand:     make(map[*Piece]*Player),
+		handCounts: make(map[handKey]int),
+	}
+	for _, piece := range board.AllPieces() {
+		z.hash ^= pieceKey(piece.Type(), piece.Color(), piece.Square())
+		z.hash ^= propertiesKey(piece, piece.Square())
+	}
+	return z
+}
+
+func (z *zobrist) Handle(event event.Event) {
+	switch e := event.(type) {
+	case PiecePlaced:
+		if holder, ok := z.inHand[e.Piece]; ok {
+			z.release(e.Piece, holder)
+		}
+		z.hash ^= pieceKey(e.Piece.Type(), e.Piece.Color(), e.Square)
+		z.hash ^= propertiesKey(e.Piece, e.Square)
+	case PieceMoved:
+		z.hash ^= pieceKey(e.Piece.Type(), e.Piece.Color(), e.From)
+		z.hash ^= pieceKey(e.Piece.Type(), e.Piece.Color(), e.To)
+		z.hash ^= propertiesKey(e.Piece, e.From)
+		z.hash ^= propertiesKey(e.Piece, e.To)
+	case PieceRemoved:
+		z.hash ^= pieceKey(e.Piece.Type(), e.Piece.Color(), e.Square)
+		z.hash ^= propertiesKey(e.Piece, e.Square)
+	case PieceCaptured:
+		if e.CapturedBy != nil {
+			z.capture(e.Piece, e.CapturedBy)
+		}
+	case PieceConverted:
+		if holder, ok := z.inHand[e.Piece]; ok {
+			z.release(e.Piece, holder)
+		}
+	case PiecePropertySet:
+		if e.Piece.IsOnBoard() {
+			z.hash ^= propertyKey(e.Piece, e.Piece.Square(), e.Name, e.From)
+			z.hash ^= propertyKey(e.Piece, e.Piece.Square(), e.Name, e.To)
+		}
+	case VariableSet:
+		z.hash ^= variableKey(e.Name, e.From)
+		z.hash ^= variableKey(e.Name, e.To)
+	}
+}
+
+func (z *zobrist) capture(piece *Piece, holder *Player) {
+	key := handKey{ty: piece.Type(), holder: holder.Color()}
+	z.hash ^= handCountKey(key, z.handCounts[key])
+	z.handCounts[key]++
+	z.hash ^= handCountKey(key, z.handCounts[key])
+	z.inHand[piece] = holder
+}
+
+func (z *zobrist) release(piece *Piece, holder *Player) {
+	key := handKey{ty: piece.Type(), holder: holder.Color()}
+	z.hash ^= handCountKey(key, z.handCounts[key])
+	z.handCounts[key]--
+	z.hash ^= handCountKey(key, z.handCounts[key])
+	delete(z.inHand, piece)
+}
+
+func (z *zobrist) changeSide(from *Player, to *Player) {
+	z.hash ^= sideKey(from.Color())
+	z.hash ^= sideKey(to.Color())
+}
+
+// The keys are derived from the names of the piece types instead of being
+// drawn at random, so that they are the same in every game using the rules.
+
+const (
+	pieceKeyKind uint64 = iota + 1
+	handKeyKind
+	sideKeyKind
+)
+
+func pieceKey(pieceType *PieceType, color color.Color, square brd.Square) uint64 {
+	return mix(pieceType.zobristSeed ^ mix(pieceKeyKind<<56^uint64(color)<<48^uint64(square.File)<<24^uint64(square.Rank)))
+}
+
+func handCountKey(key handKey, count int) uint64 {
+	if count == 0 {
+		return 0
+	}
+	return mix(key.ty.zobristSeed ^ mix(handKeyKind<<56^uint64(key.holder)<<48^uint64(count)))
+}
+
+func sideKey(color color.Color) uint64 {
+	return mix(sideKeyKind<<56 ^ uint64(color)<<48)
+}
+
+// propertiesKey combines the keys of all the properties of the piece standing
+// on the square.
+func propertiesKey(piece *Piece, square brd.Square) uint64 {
+	var result uint64
+	for name, value := range piece.props {
+		result ^= propertyKey(piece, square, name, value)
+	}
+	return result
+}
+
+func propertyKey(piece *Piece, square brd.Square, name string, value any) uint64 {
+	if value == nil {
+		return 0
+	}
+	return mix(pieceKey(piece.Type(), piece.Color(), square) ^ variableKey(name, value))
+}
+
+func variableKey(name string, value any) uint64 {
+	return mix(stringSeed(name) ^ stringSeed(fmt.Sprintf("%T:%v", value, value)))
+}
+
+func stringSeed(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix is the finalizer of the SplitMix64 generator.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
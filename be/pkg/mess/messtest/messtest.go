@@ -83,8 +83,7 @@ func OffsetMotion(t *testing.T, offsets ...board.Offset) mess.Motion {
 		MoveGenerator: func(piece *mess.Piece) []board.Square {
 			destinations := make([]board.Square, 0, len(offsets))
 			for _, offset := range offsets {
-				square := piece.Square().Offset(offset)
-				if piece.Board().Contains(square) {
+				if square, ok := piece.Board().Offset(piece.Square(), offset); ok {
 					destinations = append(destinations, square)
 				}
 			}
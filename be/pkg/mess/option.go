@@ -108,10 +108,11 @@ func (d OptionData[T]) setLeavesChildren(children []*OptionNode) {
 
 func (d OptionData[T]) filter(parentRoute Route, predicate func(Route) bool) (result OptionData[T]) {
 	for _, datum := range d {
-		route := append(parentRoute, datum.Option)
-		if datum.Children != nil {
+		// full slice expression, so that sibling routes don't share the backing array
+		route := append(parentRoute[:len(parentRoute):len(parentRoute)], datum.Option)
+		if children := datum.NonEmptyChildren(); children != nil {
 			var newChildren []*OptionNode
-			for _, child := range datum.Children {
+			for _, child := range children {
 				newChild := child.filterRoutes(route, predicate)
 				if newChild.Len() != 0 {
 					newChildren = append(newChildren, newChild)
@@ -150,7 +151,7 @@ func (d *OptionDatum[T]) NonEmptyChildren() (result []*OptionNode) {
 		return
 	}
 	for _, child := range d.Children {
-		if child != nil && child.Data.len() > 0 {
+		if child.Len() > 0 {
 			result = append(result, child)
 		}
 	}
@@ -0,0 +1,82 @@
+package mess
+
+import "fmt"
+
+// PerftDivision is the number of leaf routes reachable after playing Route.
+type PerftDivision struct {
+	Route Route
+	Nodes int
+}
+
+// Perft walks the game tree to the given depth and counts its leaf routes.
+// Comparing the result against known values validates the move generation of
+// the rules. The game is left in the same state afterwards.
+func (g *Game) Perft(depth int) (int, error) {
+	if depth <= 0 {
+		return 1, nil
+	}
+
+	routes, err := g.allRoutes()
+	if err != nil {
+		return 0, err
+	}
+	if depth == 1 {
+		return len(routes), nil
+	}
+
+	total := 0
+	for _, route := range routes {
+		nodes, err := g.perftAfter(route, depth-1)
+		if err != nil {
+			return 0, err
+		}
+		total += nodes
+	}
+	return total, nil
+}
+
+// PerftDivide is like Perft, but breaks the count down per route of the
+// current turn.
+func (g *Game) PerftDivide(depth int) ([]PerftDivision, error) {
+	if depth <= 0 {
+		return nil, nil
+	}
+
+	routes, err := g.allRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PerftDivision, 0, len(routes))
+	for _, route := range routes {
+		nodes, err := g.perftAfter(route, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, PerftDivision{Route: route, Nodes: nodes})
+	}
+	return result, nil
+}
+
+func (g *Game) perftAfter(route Route, depth int) (int, error) {
+	err := g.PlayTurn(route)
+	if err != nil {
+		g.UndoTurn()
+		return 0, fmt.Errorf("playing route %v: %w", route, err)
+	}
+
+	nodes, err := g.Perft(depth)
+
+	if revertErr := g.RevertTurn(); revertErr != nil {
+		return 0, fmt.Errorf("reverting route %v: %w", route, revertErr)
+	}
+	return nodes, err
+}
+
+func (g *Game) allRoutes() ([]Route, error) {
+	optionTree, err := g.TurnOptions()
+	if err != nil {
+		return nil, fmt.Errorf("generating turn options: %w", err)
+	}
+	return optionTree.AllRoutes(), nil
+}
@@ -6,6 +6,7 @@ import (
 	brd "github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/event"
+	"golang.org/x/exp/maps"
 )
 
 type Piece struct {
@@ -14,6 +15,7 @@ type Piece struct {
 	board  *PieceBoard
 	square brd.Square
 	moves  []*MoveGroup
+	props  map[string]any
 }
 
 func NewPiece(pieceType *PieceType, owner *Player) *Piece {
@@ -83,6 +85,62 @@ func (p *Piece) GetCapturedBy(player *Player) error {
 	return nil
 }
 
+// Property returns the value of the custom property of the given name: either
+// a bool (a flag) or an int (a counter).
+func (p *Piece) Property(name string) (any, bool) {
+	value, ok := p.props[name]
+	return value, ok
+}
+
+func (p *Piece) Properties() map[string]any {
+	return maps.Clone(p.props)
+}
+
+// SetProperty sets the custom property of the given name. The value must be
+// either a bool (a flag) or an int (a counter). The piece must be on a board,
+// so that the change is recorded and can be undone.
+func (p *Piece) SetProperty(name string, value any) error {
+	switch value.(type) {
+	case bool, int:
+	default:
+		return fmt.Errorf("property %q: value %v is neither a bool nor an int", name, value)
+	}
+	if !p.IsOnBoard() {
+		return fmt.Errorf("property %q: piece not on board", name)
+	}
+	p.setProperty(name, value)
+	return nil
+}
+
+// setProperty sets the property (or deletes it if the value is nil) and
+// notifies the board about the change.
+func (p *Piece) setProperty(name string, value any) {
+	old, _ := p.Property(name)
+	if value == nil {
+		delete(p.props, name)
+	} else {
+		if p.props == nil {
+			p.props = make(map[string]any)
+		}
+		p.props[name] = value
+	}
+	p.board.Notify(PiecePropertySet{
+		Piece: p,
+		Name:  name,
+		From:  old,
+		To:    value,
+	})
+}
+
+// PiecePropertySet is emitted when a custom property of a piece changes. From
+// and To are nil if the property was unset before or after the change.
+type PiecePropertySet struct {
+	Piece *Piece
+	Name  string
+	From  any
+	To    any
+}
+
 func (p *Piece) Moves() []*MoveGroup {
 	if p.moves == nil {
 		p.generateMoves()
@@ -114,5 +172,7 @@ func (p *Piece) Handle(event event.Event) {
 }
 
 func (p *Piece) Clone() *Piece {
-	return NewPiece(p.ty, p.owner)
+	clone := NewPiece(p.ty, p.owner)
+	clone.props = maps.Clone(p.props)
+	return clone
 }
@@ -5,21 +5,30 @@ import (
 
 	"github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/event"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type PieceBoard struct {
 	event.Subject
-	wrapped board.Board[*Piece]
+	wrapped  board.Board[*Piece]
+	disabled map[board.Square]struct{}
+	topology board.Topology
 }
 
 func NewPieceBoard(width int, height int) (*PieceBoard, error) {
+	return NewPieceBoardWithTopology(width, height, board.Rectangular)
+}
+
+func NewPieceBoardWithTopology(width int, height int, topology board.Topology) (*PieceBoard, error) {
 	board, err := board.NewBoard[*Piece](width, height)
 	if err != nil {
 		return nil, err
 	}
 	return &PieceBoard{
-		Subject: event.NewSubject(),
-		wrapped: board,
+		Subject:  event.NewSubject(),
+		wrapped:  board,
+		topology: topology,
 	}, nil
 }
 
@@ -27,9 +36,12 @@ func (b *PieceBoard) String() string {
 	return b.wrapped.String()
 }
 
+// PrettyString draws the board, marking the disabled squares with '#'.
 func (b *PieceBoard) PrettyString() string {
-	return b.wrapped.PrettyString(func(p *Piece) rune {
-		if p == nil {
+	return b.wrapped.PrettySquaresString(b.topology, func(square board.Square, p *Piece) rune {
+		if b.IsDisabled(square) {
+			return rune('#')
+		} else if p == nil {
 			return rune(' ')
 		}
 		return p.Presentation().Symbol
@@ -40,12 +52,64 @@ func (b *PieceBoard) Size() (int, int) {
 	return b.wrapped.Size()
 }
 
+func (b *PieceBoard) Topology() board.Topology {
+	return b.topology
+}
+
+// Offset returns the square offset by the given offset according to the
+// board's topology, and whether the board contains it.
+func (b *PieceBoard) Offset(square board.Square, offset board.Offset) (board.Square, bool) {
+	width, height := b.Size()
+	result := b.topology.Offset(square, offset, width, height)
+	return result, b.Contains(result)
+}
+
 func (b *PieceBoard) At(square board.Square) (*Piece, error) {
+	if b.IsDisabled(square) {
+		return nil, fmt.Errorf("square %s is disabled", square)
+	}
 	return b.wrapped.At(square)
 }
 
+// Contains returns true if the square is within the board's bounds and is not
+// disabled.
 func (b *PieceBoard) Contains(square board.Square) bool {
-	return b.wrapped.Contains(square)
+	return b.wrapped.Contains(square) && !b.IsDisabled(square)
+}
+
+// Disable makes the given squares unavailable, as if they were outside the
+// board. The squares must be empty.
+func (b *PieceBoard) Disable(squares ...board.Square) error {
+	for _, square := range squares {
+		piece, err := b.At(square)
+		if err != nil {
+			return fmt.Errorf("disabling square: %w", err)
+		} else if piece != nil {
+			return fmt.Errorf("disabling square %v: occupied by %v", square, piece)
+		}
+		if b.disabled == nil {
+			b.disabled = make(map[board.Square]struct{})
+		}
+		b.disabled[square] = struct{}{}
+	}
+	return nil
+}
+
+func (b *PieceBoard) IsDisabled(square board.Square) bool {
+	_, ok := b.disabled[square]
+	return ok
+}
+
+// DisabledSquares returns the disabled squares, ordered by rank and file.
+func (b *PieceBoard) DisabledSquares() []board.Square {
+	result := maps.Keys(b.disabled)
+	slices.SortFunc(result, func(a, b board.Square) int {
+		if a.Rank != b.Rank {
+			return a.Rank - b.Rank
+		}
+		return a.File - b.File
+	})
+	return result
 }
 
 func (b *PieceBoard) AllPieces() []*Piece {
@@ -56,7 +120,7 @@ func (b *PieceBoard) Place(piece *Piece, square board.Square) error {
 	if piece.IsOnBoard() {
 		return fmt.Errorf("piece already on a board")
 	}
-	old, err := b.wrapped.At(square)
+	old, err := b.At(square)
 	if err != nil {
 		return fmt.Errorf("getting piece at %v: %w", square, err)
 	}
@@ -101,6 +165,8 @@ func (b *PieceBoard) RemoveAt(square board.Square) error {
 func (b *PieceBoard) Replace(piece *Piece, square board.Square) error {
 	if piece.IsOnBoard() {
 		return fmt.Errorf("piece already on a board")
+	} else if b.IsDisabled(square) {
+		return fmt.Errorf("square %s is disabled", square)
 	}
 
 	old, err := b.wrapped.Place(piece, square)
@@ -166,6 +232,8 @@ type PieceCaptured struct {
 func (b *PieceBoard) Move(piece *Piece, square board.Square) error {
 	if piece.Board() != b {
 		return fmt.Errorf("piece not on board")
+	} else if b.IsDisabled(square) {
+		return fmt.Errorf("square %s is disabled", square)
 	}
 
 	_, err := b.wrapped.Place(nil, piece.Square())
@@ -198,12 +266,13 @@ type PieceMoved struct {
 
 func (b *PieceBoard) Clone() *PieceBoard {
 	width, height := b.Size()
-	clone, err := NewPieceBoard(width, height)
+	clone, err := NewPieceBoardWithTopology(width, height, b.topology)
 	if err != nil {
 		// If the previous board was created,
 		// the new one should be too
 		panic(err)
 	}
+	clone.disabled = maps.Clone(b.disabled)
 	for _, piece := range b.AllPieces() {
 		err = piece.Clone().PlaceOn(clone, piece.Square())
 		if err != nil {
@@ -3,6 +3,7 @@ package mess_test
 import (
 	"testing"
 
+	brd "github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/board/boardtest"
 	"github.com/jostrzol/mess/pkg/mess"
 	"github.com/stretchr/testify/assert"
@@ -28,3 +29,47 @@ func TestClone(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, pieceA2)
 }
+
+func TestDisable(t *testing.T) {
+	board, err := mess.NewPieceBoard(3, 3)
+	assert.NoError(t, err)
+	b2 := boardtest.NewSquare("B2")
+
+	err = board.Disable(b2, boardtest.NewSquare("A1"))
+	assert.NoError(t, err)
+
+	assert.False(t, board.Contains(b2))
+	assert.True(t, board.Contains(boardtest.NewSquare("B1")))
+	assert.Equal(t, []brd.Square{boardtest.NewSquare("A1"), b2}, board.DisabledSquares())
+	assert.Error(t, mess.NewPiece(King(t), nil).PlaceOn(board, b2))
+	assert.Contains(t, board.PrettyString(), "|# |")
+	assert.Equal(t, board.DisabledSquares(), board.Clone().DisabledSquares())
+}
+
+func TestDisableOccupied(t *testing.T) {
+	board, err := mess.NewPieceBoard(3, 3)
+	assert.NoError(t, err)
+	err = board.Place(mess.NewPiece(King(t), nil), boardtest.NewSquare("A1"))
+	assert.NoError(t, err)
+
+	err = board.Disable(boardtest.NewSquare("A1"))
+
+	assert.Error(t, err)
+}
+
+func TestOffsetCylindrical(t *testing.T) {
+	board, err := mess.NewPieceBoardWithTopology(3, 3, brd.Cylindrical)
+	assert.NoError(t, err)
+	err = board.Disable(boardtest.NewSquare("C2"))
+	assert.NoError(t, err)
+
+	square, ok := board.Offset(boardtest.NewSquare("A1"), brd.Offset{X: -1, Y: 0})
+	assert.True(t, ok)
+	assert.Equal(t, boardtest.NewSquare("C1"), square)
+
+	_, ok = board.Offset(boardtest.NewSquare("A2"), brd.Offset{X: -1, Y: 0})
+	assert.False(t, ok, "disabled square")
+	_, ok = board.Offset(boardtest.NewSquare("A3"), brd.Offset{X: 0, Y: 1})
+	assert.False(t, ok, "ranks do not wrap")
+	assert.Equal(t, brd.Cylindrical, board.Clone().Topology())
+}
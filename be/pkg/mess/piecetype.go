@@ -5,16 +5,20 @@ import (
 	"unicode/utf8"
 
 	"github.com/jostrzol/mess/pkg/color"
+	"golang.org/x/exp/maps"
 )
 
 type PieceType struct {
 	name         string
 	presentation map[color.Color]Presentation
 	motions      chainMotions
+	zobristSeed  uint64
 }
 
 type Presentation struct {
 	Symbol rune
+	// Code represents the piece in the setup notation. Defaults to Symbol.
+	Code   rune
 	Icon   AssetKey
 	Rotate bool
 }
@@ -26,7 +30,8 @@ func NewPieceType(name string) *PieceType {
 			color.Black: {Symbol: defaultSymbol(color.Black, name)},
 			color.White: {Symbol: defaultSymbol(color.White, name)},
 		},
-		motions: make(chainMotions, 0),
+		motions:     make(chainMotions, 0),
+		zobristSeed: stringSeed(name),
 	}
 }
 
@@ -42,7 +47,27 @@ func (t *PieceType) SetPresentation(color color.Color, presentation Presentation
 }
 
 func (t *PieceType) Presentation(color color.Color) Presentation {
-	return t.presentation[color]
+	presentation, ok := t.presentation[color]
+	if !ok {
+		return Presentation{Symbol: defaultSymbol(color, t.Name())}
+	}
+	return presentation
+}
+
+// Code returns the character representing the piece type of the given color
+// in the setup notation (see State.Setup).
+func (t *PieceType) Code(color color.Color) rune {
+	presentation := t.Presentation(color)
+	if presentation.Code == 0 {
+		return presentation.Symbol
+	}
+	return presentation.Code
+}
+
+// Presentations returns the presentations of the piece type for all the colors
+// it was configured for.
+func (t *PieceType) Presentations() map[color.Color]Presentation {
+	return maps.Clone(t.presentation)
 }
 
 func defaultSymbol(col color.Color, name string) rune {
@@ -53,10 +78,8 @@ func defaultSymbol(col color.Color, name string) rune {
 	switch col {
 	case color.Black:
 		return unicode.ToLower(r)
-	case color.White:
-		return unicode.ToUpper(r)
 	default:
-		panic("unreachable")
+		return unicode.ToUpper(r)
 	}
 }
 
@@ -18,19 +18,33 @@ type Player struct {
 	forwardDirection brd.Offset
 }
 
+// PlayerConfig describes a single player of a game.
+type PlayerConfig struct {
+	Color            color.Color
+	ForwardDirection brd.Offset
+}
+
+// DefaultPlayerConfigs are the players of a classic two-player game, listed
+// in their turn order.
+var DefaultPlayerConfigs = []PlayerConfig{
+	{Color: color.White, ForwardDirection: brd.Offset{X: 0, Y: 1}},
+	{Color: color.Black, ForwardDirection: brd.Offset{X: 0, Y: -1}},
+}
+
 func NewPlayers(board event.Subject) map[color.Color]*Player {
-	players := map[color.Color]*Player{
-		color.White: {
-			forwardDirection: brd.Offset{X: 0, Y: 1},
-		},
-		color.Black: {
-			forwardDirection: brd.Offset{X: 0, Y: -1},
-		},
-	}
-	for color, player := range players {
-		player.color = color
-		player.pieces = make(map[*Piece]struct{})
-		player.captures = make(map[*Piece]struct{})
+	return NewPlayersFromConfigs(board, DefaultPlayerConfigs)
+}
+
+func NewPlayersFromConfigs(board event.Subject, configs []PlayerConfig) map[color.Color]*Player {
+	players := make(map[color.Color]*Player, len(configs))
+	for _, config := range configs {
+		player := &Player{
+			color:            config.Color,
+			pieces:           make(map[*Piece]struct{}),
+			captures:         make(map[*Piece]struct{}),
+			forwardDirection: config.ForwardDirection,
+		}
+		players[config.Color] = player
 		board.Observe(player)
 	}
 	return players
@@ -63,19 +77,36 @@ func (p *Player) ConvertAndReleasePiece(pieceType *PieceType, board *PieceBoard,
 		return fmt.Errorf("player %v does not have a capture of type %v", p, pieceType)
 	}
 
+	occupant, err := board.At(square)
+	if err != nil {
+		return fmt.Errorf("getting piece at %v: %w", square, err)
+	} else if occupant != nil {
+		return fmt.Errorf("releasing a piece on %v: already occupied by %v", square, occupant)
+	}
+
 	piece := captures[i]
 	oldOwner := piece.owner
 	piece.owner = p
+	board.Notify(PieceConverted{
+		Piece: piece,
+		From:  oldOwner,
+		To:    p,
+	})
 
-	err := board.Place(piece, square)
+	err = board.Place(piece, square)
 	if err != nil {
-		piece.owner = oldOwner
 		return fmt.Errorf("placing the released piece at destination square: %w", err)
 	}
 
 	return nil
 }
 
+type PieceConverted struct {
+	Piece *Piece
+	From  *Player
+	To    *Player
+}
+
 func (p *Player) ForwardDirection() board.Offset {
 	return p.forwardDirection
 }
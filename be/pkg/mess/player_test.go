@@ -18,7 +18,7 @@ func TestNewPlayers(t *testing.T) {
 	players := mess.NewPlayers(board)
 	assert.Len(t, players, 2)
 
-	for _, color := range color.ColorValues() {
+	for _, color := range []color.Color{color.White, color.Black} {
 		assert.Contains(t, players, color)
 		player := players[color]
 		assert.Equal(t, player.Color(), color)
@@ -0,0 +1,121 @@
+package mess
+
+// PositionExtraFunc returns the part of the position, which is not visible on
+// the board, e.g. castling rights in chess.
+type PositionExtraFunc func(state *State) string
+
+func (s *State) SetPositionExtra(positionExtra PositionExtraFunc) {
+	s.positionExtra = positionExtra
+}
+
+// PositionKey identifies the current position: the hash of the state combined
+// with the extra state declared by the rules. Equal positions have equal keys.
+func (s *State) PositionKey() uint64 {
+	key := s.Hash()
+	if s.positionExtra != nil {
+		key ^= mix(stringSeed(s.positionExtra(s)))
+	}
+	return key
+}
+
+// RepetitionCount returns how many times the position from the start of the
+// current turn has occurred in the game so far, including the current
+// occurrence. Returns 1 if the position has not been recorded.
+func (s *State) RepetitionCount() int {
+	if len(s.positions) <= s.turnNumber || s.positions[s.turnNumber] == 0 {
+		return 1
+	}
+	return s.positionCounts[s.positions[s.turnNumber]]
+}
+
+// PliesSinceCapture returns the number of turns played since the last capture,
+// or since the start of the game if there was none. A game set up from
+// a position starts at its turn number.
+func (s *State) PliesSinceCapture() int {
+	return s.pliesSince(s.captureTurns)
+}
+
+// PliesSinceMove returns the number of turns played since the last move of a
+// piece of the given type, or since the start of the game if there was none.
+func (s *State) PliesSinceMove(pieceType *PieceType) int {
+	return s.pliesSince(s.moveTurns[pieceType])
+}
+
+func (s *State) pliesSince(turns []int) int {
+	if len(turns) == 0 {
+		return s.turnNumber - s.startTurnNumber
+	}
+	return s.turnNumber - turns[len(turns)-1] - 1
+}
+
+// recordPosition records the key of the position at the start of the current
+// turn, unless it is already recorded or the turn has already begun.
+func (s *State) recordPosition() {
+	if s.isRecordingPosition || len(s.positions) > s.turnNumber || len(s.record) > s.turnNumber {
+		return
+	}
+	s.isRecordingPosition = true
+	defer func() { s.isRecordingPosition = false }()
+
+	key := s.PositionKey()
+	for len(s.positions) < s.turnNumber {
+		// the start of some of the previous turns was missed
+		s.positions = append(s.positions, 0)
+	}
+	s.positions = append(s.positions, key)
+	s.positionCounts[key]++
+}
+
+// recordProgress records the captures and the moves made in the turn being
+// ended, so that the plies since them are counted without scanning the record.
+func (s *State) recordProgress() {
+	if len(s.record) <= s.turnNumber {
+		return
+	}
+	for _, event := range s.record[s.turnNumber] {
+		switch e := event.(type) {
+		case PieceCaptured:
+			s.captureTurns = appendTurn(s.captureTurns, s.turnNumber)
+		case PieceMoved:
+			pieceType := e.Piece.Type()
+			s.moveTurns[pieceType] = appendTurn(s.moveTurns[pieceType], s.turnNumber)
+		}
+	}
+}
+
+func appendTurn(turns []int, turn int) []int {
+	if len(turns) != 0 && turns[len(turns)-1] == turn {
+		return turns
+	}
+	return append(turns, turn)
+}
+
+// revertProgress forgets the positions and the progress recorded after the
+// start of the current turn.
+func (s *State) revertProgress() {
+	for len(s.positions) > s.turnNumber+1 {
+		key := s.positions[len(s.positions)-1]
+		s.positions = s.positions[:len(s.positions)-1]
+		if key != 0 {
+			s.positionCounts[key]--
+		}
+	}
+	s.captureTurns = dropTurn(s.captureTurns, s.turnNumber)
+	for pieceType, turns := range s.moveTurns {
+		s.moveTurns[pieceType] = dropTurn(turns, s.turnNumber)
+	}
+}
+
+func dropTurn(turns []int, turn int) []int {
+	if len(turns) != 0 && turns[len(turns)-1] == turn {
+		return turns[:len(turns)-1]
+	}
+	return turns
+}
+
+func (s *State) resetProgress() {
+	s.positions = nil
+	s.positionCounts = make(map[uint64]int)
+	s.captureTurns = nil
+	s.moveTurns = make(map[*PieceType][]int)
+}
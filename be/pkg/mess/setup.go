@@ -0,0 +1,234 @@
+package mess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	brd "github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/color"
+	"golang.org/x/exp/slices"
+)
+
+// The setup notation describes a position of the game in a single line,
+// similarly to FEN in chess. It consists of four fields separated by spaces:
+//   - the board: the ranks from the last to the first separated by "/", each
+//     listing the codes of the pieces from the first file to the last, with
+//     the runs of empty squares written as numbers,
+//   - the color of the player to move,
+//   - the captured pieces held by the players, written with the codes of the
+//     holders' colors, or "-" if there are none,
+//   - the number of turns played so far.
+//
+// The code of a piece is set by its presentation (see PieceType.Code). E.g.
+// the starting position of chess is:
+//
+//	rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR white - 0
+
+// Setup returns the current position in the setup notation.
+func (s *State) Setup() (string, error) {
+	codes, _, err := s.pieceCodes()
+	if err != nil {
+		return "", err
+	}
+
+	width, height := s.board.Size()
+	ranks := make([]string, 0, height)
+	for y := height - 1; y >= 0; y-- {
+		var rank strings.Builder
+		empty := 0
+		for x := 0; x < width; x++ {
+			square := brd.SquareFromCoords(x, y)
+			var piece *Piece
+			if s.board.Contains(square) {
+				piece, _ = s.board.At(square)
+			}
+			if piece == nil {
+				empty++
+				continue
+			}
+			if empty != 0 {
+				rank.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			rank.WriteRune(codes[pieceCodeKey{ty: piece.Type(), color: piece.Color()}])
+		}
+		if empty != 0 {
+			rank.WriteString(strconv.Itoa(empty))
+		}
+		ranks = append(ranks, rank.String())
+	}
+
+	var hand strings.Builder
+	for _, player := range s.turnOrder {
+		captures := player.Captures()
+		slices.SortFunc(captures, func(a, b *Piece) int { return strings.Compare(a.Type().Name(), b.Type().Name()) })
+		for _, piece := range captures {
+			hand.WriteRune(codes[pieceCodeKey{ty: piece.Type(), color: player.Color()}])
+		}
+	}
+	if hand.Len() == 0 {
+		hand.WriteString("-")
+	}
+
+	return fmt.Sprintf("%s %s %s %d",
+		strings.Join(ranks, "/"), s.currentPlayer.Color(), hand.String(), s.turnNumber), nil
+}
+
+// LoadSetup sets up the position given in the setup notation. The board must
+// be empty and no turn may have been played yet.
+func (s *State) LoadSetup(setup string) error {
+	if len(s.board.AllPieces()) != 0 || s.turnNumber != 0 || len(s.record) != 0 {
+		return fmt.Errorf("the game is already set up")
+	}
+	fields := strings.Fields(setup)
+	if len(fields) != 4 {
+		return fmt.Errorf("expected 4 fields, got %d", len(fields))
+	}
+	_, pieceTypes, err := s.pieceCodes()
+	if err != nil {
+		return err
+	}
+
+	placements, err := s.parseSetupBoard(fields[0], pieceTypes)
+	if err != nil {
+		return fmt.Errorf("parsing board: %w", err)
+	}
+	playerColor, err := color.ColorString(fields[1])
+	if err != nil {
+		return fmt.Errorf("parsing player to move: %w", err)
+	}
+	player, ok := s.players[playerColor]
+	if !ok {
+		return fmt.Errorf("parsing player to move: no %s player in the game", playerColor)
+	}
+	hand, err := parseSetupHand(fields[2], pieceTypes)
+	if err != nil {
+		return fmt.Errorf("parsing captured pieces: %w", err)
+	}
+	turnNumber, err := strconv.Atoi(fields[3])
+	if err != nil || turnNumber < 0 {
+		return fmt.Errorf("parsing turn number: %q is not a non-negative integer", fields[3])
+	}
+
+	s.isRecording = false
+	defer func() { s.isRecording = true }()
+
+	for _, placement := range placements {
+		piece := NewPiece(placement.ty, s.players[placement.color])
+		if err := piece.PlaceOn(s.board, placement.square); err != nil {
+			return fmt.Errorf("placing %v: %w", piece, err)
+		}
+	}
+	for _, key := range hand {
+		holder := s.players[key.color]
+		piece := NewPiece(key.ty, s.OpponentTo(holder))
+		s.board.Notify(PieceCaptured{
+			Piece:        piece,
+			CapturedBy:   holder,
+			CapturedFrom: piece.Owner(),
+		})
+	}
+
+	s.zobrist.changeSide(s.currentPlayer, player)
+	s.currentPlayer = player
+	s.turnNumber = turnNumber
+	s.startTurnNumber = turnNumber
+	s.resetProgress()
+	s.validMoves = nil
+	return nil
+}
+
+type pieceCodeKey struct {
+	ty    *PieceType
+	color color.Color
+}
+
+type setupPlacement struct {
+	pieceCodeKey
+	square brd.Square
+}
+
+// pieceCodes maps the piece types of all the players' colors to their codes
+// and back. The codes must be unambiguous.
+func (s *State) pieceCodes() (map[pieceCodeKey]rune, map[rune]pieceCodeKey, error) {
+	pieceTypes := s.PieceTypes()
+	slices.SortFunc(pieceTypes, func(a, b *PieceType) int { return strings.Compare(a.Name(), b.Name()) })
+
+	codes := make(map[pieceCodeKey]rune)
+	keys := make(map[rune]pieceCodeKey)
+	for _, pieceType := range pieceTypes {
+		for _, player := range s.turnOrder {
+			key := pieceCodeKey{ty: pieceType, color: player.Color()}
+			code := pieceType.Code(player.Color())
+			if isSetupDigit(code) || unicode.IsSpace(code) || code == '/' || code == '-' {
+				return nil, nil, fmt.Errorf("code %q of %s %s is reserved", code, key.color, key.ty)
+			}
+			if other, ok := keys[code]; ok {
+				return nil, nil, fmt.Errorf("code %q is ambiguous: used by %s %s and %s %s",
+					code, other.color, other.ty, key.color, key.ty)
+			}
+			codes[key] = code
+			keys[code] = key
+		}
+	}
+	return codes, keys, nil
+}
+
+func (s *State) parseSetupBoard(field string, pieceTypes map[rune]pieceCodeKey) ([]setupPlacement, error) {
+	width, height := s.board.Size()
+	ranks := strings.Split(field, "/")
+	if len(ranks) != height {
+		return nil, fmt.Errorf("expected %d ranks, got %d", height, len(ranks))
+	}
+
+	result := make([]setupPlacement, 0)
+	for i, rank := range ranks {
+		y := height - 1 - i
+		x := 0
+		empty := 0
+		for _, code := range rank {
+			if isSetupDigit(code) {
+				empty = empty*10 + int(code-'0')
+				continue
+			}
+			x += empty
+			empty = 0
+			key, ok := pieceTypes[code]
+			if !ok {
+				return nil, fmt.Errorf("unknown piece code %q", code)
+			}
+			square := brd.SquareFromCoords(x, y)
+			if !s.board.Contains(square) {
+				return nil, fmt.Errorf("piece on %s, which is not on the board", square)
+			}
+			result = append(result, setupPlacement{pieceCodeKey: key, square: square})
+			x++
+		}
+		x += empty
+		if x != width {
+			return nil, fmt.Errorf("rank %d: expected %d files, got %d", y+1, width, x)
+		}
+	}
+	return result, nil
+}
+
+func parseSetupHand(field string, pieceTypes map[rune]pieceCodeKey) ([]pieceCodeKey, error) {
+	if field == "-" {
+		return nil, nil
+	}
+	result := make([]pieceCodeKey, 0, len(field))
+	for _, code := range field {
+		key, ok := pieceTypes[code]
+		if !ok {
+			return nil, fmt.Errorf("unknown piece code %q", code)
+		}
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func isSetupDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
@@ -6,34 +6,67 @@ import (
 	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/event"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type State struct {
-	board             *PieceBoard
-	players           map[color.Color]*Player
-	currentPlayer     *Player
-	record            []Turn
-	isRecording       bool
-	validators        chainStateValidators
-	validMoves        []*MoveGroup
-	turnNumber        int
+	board         *PieceBoard
+	players       map[color.Color]*Player
+	turnOrder     []*Player
+	currentPlayer *Player
+	record        []Turn
+	isRecording   bool
+	validators    chainStateValidators
+	validMoves    []*MoveGroup
+	turnNumber    int
+	// startTurnNumber is the number of the turn the game started from, e.g.
+	// when it was set up from a position in the middle of a game.
+	startTurnNumber   int
 	isGeneratingMoves bool
 	pieceTypes        map[string]*PieceType
-	Assets            Assets
+	// positions contains the keys of the positions at the start of each turn,
+	// or 0 if the start of the turn was missed.
+	positions           []uint64
+	positionCounts      map[uint64]int
+	positionExtra       PositionExtraFunc
+	isRecordingPosition bool
+	// captureTurns and moveTurns contain the numbers of the finished turns with
+	// a capture and with a move of a piece of the given type.
+	captureTurns []int
+	moveTurns    map[*PieceType][]int
+	zobrist      zobrist
+	variables    map[string]any
+	zones        map[string]*Zone
+	Assets       Assets
 }
 
 func NewState(board *PieceBoard) *State {
-	players := NewPlayers(board)
+	return NewStateWithPlayers(board, DefaultPlayerConfigs)
+}
+
+// NewStateWithPlayers creates a state for the given players. The players take
+// their turns in the order of the configs.
+func NewStateWithPlayers(board *PieceBoard, configs []PlayerConfig) *State {
+	players := NewPlayersFromConfigs(board, configs)
+	turnOrder := make([]*Player, 0, len(configs))
+	for _, config := range configs {
+		turnOrder = append(turnOrder, players[config.Color])
+	}
 	state := &State{
 		board:         board,
 		players:       players,
-		currentPlayer: players[color.White],
+		turnOrder:     turnOrder,
+		currentPlayer: turnOrder[0],
 		record:        []Turn{},
 		isRecording:   true,
 		turnNumber:    0,
 		pieceTypes:    make(map[string]*PieceType),
 		Assets:        make(Assets),
+		zobrist:       newZobrist(board, turnOrder[0]),
+		variables:     make(map[string]any),
+		zones:         make(map[string]*Zone),
 	}
+	state.resetProgress()
 	board.Observe(state)
 	return state
 }
@@ -50,8 +83,9 @@ func (s *State) Board() *PieceBoard {
 	return s.board
 }
 
+// Players returns all the players in their turn order.
 func (s *State) Players() []*Player {
-	return maps.Values(s.players)
+	return slices.Clone(s.turnOrder)
 }
 
 func (s *State) Player(color color.Color) *Player {
@@ -70,20 +104,38 @@ func (s *State) CurrentOpponent() *Player {
 	return s.OpponentTo(s.currentPlayer)
 }
 
+// OpponentTo returns the player taking the turn after the given player.
+// In a two-player game it's simply the other player.
 func (s *State) OpponentTo(player *Player) *Player {
-	var opponentsColor color.Color
-	switch player.Color() {
-	case color.White:
-		opponentsColor = color.Black
-	case color.Black:
-		opponentsColor = color.White
+	return s.playerAfter(player, 1)
+}
+
+// Opponents returns all the players other than the given one, in the turn
+// order starting from the one after the given player.
+func (s *State) Opponents(player *Player) []*Player {
+	result := make([]*Player, 0, len(s.turnOrder)-1)
+	for i := 1; i < len(s.turnOrder); i++ {
+		result = append(result, s.playerAfter(player, i))
+	}
+	return result
+}
+
+func (s *State) playerAfter(player *Player, n int) *Player {
+	i := slices.Index(s.turnOrder, player)
+	if i == -1 {
+		panic(fmt.Errorf("player %v not found", player))
 	}
-	return s.Player(opponentsColor)
+	nPlayers := len(s.turnOrder)
+	return s.turnOrder[((i+n)%nPlayers+nPlayers)%nPlayers]
 }
 
 func (s *State) EndTurn() {
-	s.currentPlayer = s.CurrentOpponent()
+	s.recordProgress()
+	next := s.CurrentOpponent()
+	s.zobrist.changeSide(s.currentPlayer, next)
+	s.currentPlayer = next
 	s.turnNumber++
+	s.recordPosition()
 }
 
 func (s *State) TurnNumber() int {
@@ -123,6 +175,7 @@ func (s *State) ValidMoves() []*MoveGroup {
 }
 
 func (s *State) generateValidMoves() {
+	s.recordPosition()
 	s.isGeneratingMoves = true
 	defer func() { s.isGeneratingMoves = false }()
 
@@ -154,6 +207,7 @@ func (s *State) validateMove(move *Move) bool {
 }
 
 func (s *State) Handle(event event.Event) {
+	s.zobrist.Handle(event)
 	if !s.isRecording {
 		return
 	}
@@ -164,6 +218,11 @@ func (s *State) Handle(event event.Event) {
 		return
 	}
 
+	for len(s.record) < s.turnNumber {
+		// the previous turns did not produce any events
+		s.record = append(s.record, Turn{})
+	}
+
 	var turn Turn
 	if len(s.record) != s.turnNumber {
 		// not the first move in the round -> load it
@@ -184,7 +243,7 @@ func (s *State) Handle(event event.Event) {
 }
 
 func (s *State) UndoTurn() {
-	if len(s.record) == 0 {
+	if len(s.record) <= s.turnNumber {
 		return
 	}
 
@@ -214,12 +273,60 @@ func (s *State) UndoTurn() {
 			if err != nil {
 				panic(err)
 			}
+		case PieceConverted:
+			e.Piece.owner = e.From
+			s.board.Notify(PieceCaptured{
+				Piece:        e.Piece,
+				CapturedBy:   e.To,
+				CapturedFrom: e.From,
+			})
+		case PiecePropertySet:
+			e.Piece.setProperty(e.Name, e.From)
+		case VariableSet:
+			s.setVariable(e.Name, e.From)
 		}
 	}
 }
 
+// RevertTurn reverts the last finished turn, so that its player can play it
+// again. Unlike UndoTurn, which only undoes the events of the turn in progress,
+// it also restores the turn number and the current player.
+func (s *State) RevertTurn() error {
+	if s.turnNumber == s.startTurnNumber {
+		return ErrNoTurnToRevert
+	}
+
+	s.UndoTurn()
+	s.turnNumber--
+	previous := s.playerAfter(s.currentPlayer, -1)
+	s.zobrist.changeSide(s.currentPlayer, previous)
+	s.currentPlayer = previous
+	s.UndoTurn()
+	s.revertProgress()
+
+	s.validMoves = nil
+	return nil
+}
+
+var ErrNoTurnToRevert = fmt.Errorf("no turn to revert")
+
+// Record returns the turns played so far. A game set up from a position only
+// records the turns played after its turn number.
 func (s *State) Record() []Turn {
-	return s.record
+	if len(s.record) <= s.startTurnNumber {
+		return []Turn{}
+	}
+	return s.record[s.startTurnNumber:]
+}
+
+// LastTurn returns the events of the last finished turn. Returns nil if no turn
+// has been finished yet or if the last one made no changes.
+func (s *State) LastTurn() Turn {
+	i := s.turnNumber - 1
+	if i < s.startTurnNumber || i >= len(s.record) {
+		return nil
+	}
+	return s.record[i]
 }
 
 type Turn []event.Event
@@ -3,6 +3,7 @@ package mess_test
 import (
 	"testing"
 
+	brd "github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/board/boardtest"
 	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/mess"
@@ -22,7 +23,7 @@ func (s *StateSuite) SetupTest() {
 }
 
 func (s *StateSuite) TestGetPlayer() {
-	for _, color := range color.ColorValues() {
+	for _, color := range []color.Color{color.White, color.Black} {
 		s.Run(color.String(), func() {
 			player := s.state.Player(color)
 			s.Equal(player.Color(), color)
@@ -46,6 +47,28 @@ func (s *StateSuite) TestEndTurn() {
 	s.Equal(s.state.Player(color.Black), secondTurnPlayer)
 }
 
+func (s *StateSuite) TestTurnOrderManyPlayers() {
+	board, err := mess.NewPieceBoard(8, 8)
+	s.NoError(err)
+	state := mess.NewStateWithPlayers(board, []mess.PlayerConfig{
+		{Color: color.Red},
+		{Color: color.Green},
+		{Color: color.Blue},
+	})
+	red, green, blue := state.Player(color.Red), state.Player(color.Green), state.Player(color.Blue)
+
+	s.Equal([]*mess.Player{red, green, blue}, state.Players())
+	s.Equal([]*mess.Player{blue, red}, state.Opponents(green))
+	for _, expected := range []*mess.Player{red, green, blue, red} {
+		s.Equal(expected, state.CurrentPlayer())
+		state.EndTurn()
+	}
+
+	err = state.RevertTurn()
+	s.NoError(err)
+	s.Equal(red, state.CurrentPlayer())
+}
+
 func (s *StateSuite) TestUndoNothing() {
 	s.state.UndoTurn()
 }
@@ -127,6 +150,173 @@ func (s *StateSuite) TestUndoCapture() {
 	s.Equal(knight, pieceA2)
 }
 
+func (s *StateSuite) TestUndoSetProperty() {
+	rook := mess.NewPiece(Rook(s.T()), s.state.CurrentPlayer())
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	err = rook.SetProperty("moves", 1)
+	s.NoError(err)
+	s.state.EndTurn()
+
+	err = rook.SetProperty("moves", 2)
+	s.NoError(err)
+	err = rook.SetProperty("moved", true)
+	s.NoError(err)
+
+	s.state.UndoTurn()
+
+	s.Equal(map[string]any{"moves": 1}, rook.Properties())
+}
+
+func (s *StateSuite) TestSetPropertyInvalid() {
+	rook := mess.NewPiece(Rook(s.T()), s.state.CurrentPlayer())
+	err := rook.SetProperty("moved", true)
+	s.Error(err, "piece not on board")
+
+	err = rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	err = rook.SetProperty("name", "rook")
+	s.Error(err, "unsupported value type")
+}
+
+func (s *StateSuite) TestZone() {
+	d4, e4, e8 := boardtest.NewSquare("D4"), boardtest.NewSquare("E4"), boardtest.NewSquare("E8")
+	err := s.state.AddZone(mess.NewZone("center", []brd.Square{d4, e4}, map[color.Color][]brd.Square{
+		color.White: {e8},
+	}))
+	s.NoError(err)
+
+	zone, ok := s.state.Zone("center")
+	s.True(ok)
+	white, black := color.White, color.Black
+	s.True(zone.Contains(d4, &black))
+	s.True(zone.Contains(e8, &white))
+	s.False(zone.Contains(e8, &black))
+	s.True(zone.Contains(e8, nil))
+	s.Equal([]brd.Square{d4, e4}, zone.Squares(&black))
+	s.Equal([]brd.Square{d4, e4, e8}, zone.Squares(nil))
+	s.Equal([]*mess.Zone{zone}, s.state.Zones())
+}
+
+func (s *StateSuite) TestZoneInvalid() {
+	err := s.state.AddZone(mess.NewZone("center", []brd.Square{boardtest.NewSquare("D4")}, nil))
+	s.NoError(err)
+
+	err = s.state.AddZone(mess.NewZone("center", nil, nil))
+	s.Error(err, "duplicate name")
+	err = s.state.AddZone(mess.NewZone("outside", []brd.Square{boardtest.NewSquare("I1")}, nil))
+	s.Error(err, "square outside of the board")
+	err = s.state.AddZone(mess.NewZone("camp", nil, map[color.Color][]brd.Square{
+		color.Red: {boardtest.NewSquare("A1")},
+	}))
+	s.Error(err, "color not in the game")
+}
+
+func (s *StateSuite) TestRevertNothing() {
+	err := s.state.RevertTurn()
+	s.ErrorIs(err, mess.ErrNoTurnToRevert)
+}
+
+func (s *StateSuite) TestRevertTurn() {
+	rook := mess.NewPiece(Rook(s.T()), s.state.CurrentPlayer())
+	a1 := boardtest.NewSquare("A1")
+	err := rook.PlaceOn(s.state.Board(), a1)
+	s.NoError(err)
+
+	a2 := boardtest.NewSquare("A2")
+	err = rook.MoveTo(a2)
+	s.NoError(err)
+
+	s.state.EndTurn()
+
+	err = s.state.RevertTurn()
+	s.NoError(err)
+
+	s.Equal(0, s.state.TurnNumber())
+	s.Equal(s.state.Player(color.White), s.state.CurrentPlayer())
+
+	pieceA1, err := s.state.Board().At(a1)
+	s.NoError(err)
+	s.Equal(rook, pieceA1)
+
+	pieceA2, err := s.state.Board().At(a2)
+	s.NoError(err)
+	s.Nil(pieceA2)
+}
+
+func (s *StateSuite) TestProgressCountersRevert() {
+	rookType := Rook(s.T())
+	rook := mess.NewPiece(rookType, s.state.CurrentPlayer())
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	s.state.EndTurn()
+	s.state.EndTurn()
+
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	s.state.EndTurn()
+
+	s.Equal(0, s.state.PliesSinceMove(rookType))
+	s.Equal(3, s.state.PliesSinceCapture())
+
+	err = s.state.RevertTurn()
+	s.NoError(err)
+
+	s.Equal(2, s.state.PliesSinceMove(rookType))
+	s.Equal(2, s.state.PliesSinceCapture())
+}
+
+func (s *StateSuite) TestLastTurn() {
+	s.Nil(s.state.LastTurn())
+
+	rook := mess.NewPiece(Rook(s.T()), s.state.CurrentPlayer())
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	s.state.EndTurn()
+
+	s.Contains(s.state.LastTurn(), mess.PieceMoved{
+		Piece: rook,
+		From:  boardtest.NewSquare("A1"),
+		To:    boardtest.NewSquare("A2"),
+	})
+
+	s.state.EndTurn()
+	s.Nil(s.state.LastTurn())
+}
+
+func (s *StateSuite) TestRevertReleasedCapture() {
+	white := s.state.Player(color.White)
+	black := s.state.Player(color.Black)
+
+	rook := mess.NewPiece(Rook(s.T()), white)
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	knight := mess.NewPiece(Knight(s.T()), black)
+	err = knight.PlaceOn(s.state.Board(), boardtest.NewSquare("A2"))
+	s.NoError(err)
+
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	s.state.EndTurn()
+	s.state.EndTurn()
+
+	b1 := boardtest.NewSquare("B1")
+	err = white.ConvertAndReleasePiece(knight.Type(), s.state.Board(), b1)
+	s.NoError(err)
+	s.state.EndTurn()
+
+	err = s.state.RevertTurn()
+	s.NoError(err)
+
+	pieceB1, err := s.state.Board().At(b1)
+	s.NoError(err)
+	s.Nil(pieceB1)
+	s.Equal(black, knight.Owner())
+	s.Contains(white.Captures(), knight)
+}
+
 func (s *StateSuite) TestValidMoves() {
 	king := mess.NewPiece(King(s.T()), s.state.CurrentPlayer())
 	err := king.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
@@ -151,6 +341,186 @@ func (s *StateSuite) TestValidMovesWithValidator() {
 	messtest.MovesMatch(s.T(), moves, messtest.MovesMatcher(king, "B1"))
 }
 
+func (s *StateSuite) TestHashSideToMove() {
+	initial := s.state.Hash()
+
+	s.state.EndTurn()
+	s.NotEqual(initial, s.state.Hash())
+
+	err := s.state.RevertTurn()
+	s.NoError(err)
+	s.Equal(initial, s.state.Hash())
+}
+
+func (s *StateSuite) TestHashTransposition() {
+	rook := mess.NewPiece(Rook(s.T()), s.state.CurrentPlayer())
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	s.state.EndTurn()
+	s.state.EndTurn()
+
+	board, err := mess.NewPieceBoard(8, 8)
+	s.NoError(err)
+	other := mess.NewState(board)
+	err = mess.NewPiece(Rook(s.T()), other.CurrentPlayer()).PlaceOn(board, boardtest.NewSquare("A2"))
+	s.NoError(err)
+
+	s.Equal(other.Hash(), s.state.Hash())
+	s.Equal(s.state.Hash(), mess.NewState(s.state.Board().Clone()).Hash())
+}
+
+func (s *StateSuite) TestHashProperty() {
+	rook := mess.NewPiece(Rook(s.T()), s.state.CurrentPlayer())
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	initial := s.state.Hash()
+
+	err = rook.SetProperty("moved", true)
+	s.NoError(err)
+	withProperty := s.state.Hash()
+	s.NotEqual(initial, withProperty)
+
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	err = rook.MoveTo(boardtest.NewSquare("A1"))
+	s.NoError(err)
+	s.Equal(withProperty, s.state.Hash())
+
+	s.state.UndoTurn()
+	s.Equal(initial, s.state.Hash())
+}
+
+func (s *StateSuite) TestHashCapture() {
+	white := s.state.Player(color.White)
+	black := s.state.Player(color.Black)
+
+	rook := mess.NewPiece(Rook(s.T()), white)
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	knight := mess.NewPiece(Knight(s.T()), black)
+	err = knight.PlaceOn(s.state.Board(), boardtest.NewSquare("A2"))
+	s.NoError(err)
+	initial := s.state.Hash()
+
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	captured := s.state.Hash()
+
+	s.state.UndoTurn()
+	s.Equal(initial, s.state.Hash())
+
+	err = knight.Remove()
+	s.NoError(err)
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	s.NotEqual(captured, s.state.Hash(), "the capture in hand should change the hash")
+}
+
+func (s *StateSuite) TestHashRevertReleasedCapture() {
+	white := s.state.Player(color.White)
+	black := s.state.Player(color.Black)
+
+	rook := mess.NewPiece(Rook(s.T()), white)
+	err := rook.PlaceOn(s.state.Board(), boardtest.NewSquare("A1"))
+	s.NoError(err)
+	knight := mess.NewPiece(Knight(s.T()), black)
+	err = knight.PlaceOn(s.state.Board(), boardtest.NewSquare("A2"))
+	s.NoError(err)
+
+	err = rook.MoveTo(boardtest.NewSquare("A2"))
+	s.NoError(err)
+	s.state.EndTurn()
+	s.state.EndTurn()
+	beforeRelease := s.state.Hash()
+
+	err = white.ConvertAndReleasePiece(knight.Type(), s.state.Board(), boardtest.NewSquare("B1"))
+	s.NoError(err)
+	s.state.EndTurn()
+	s.NotEqual(beforeRelease, s.state.Hash())
+
+	err = s.state.RevertTurn()
+	s.NoError(err)
+	s.Equal(beforeRelease, s.state.Hash())
+}
+
+func (s *StateSuite) TestSetup() {
+	s.state.AddPieceType(Rook(s.T()))
+	s.state.AddPieceType(Knight(s.T()))
+
+	setup := "7r/8/8/8/8/8/1R6/k7 black Rk 12"
+	err := s.state.LoadSetup(setup)
+	s.NoError(err)
+
+	rook, err := s.state.Board().At(boardtest.NewSquare("H8"))
+	s.NoError(err)
+	s.Equal("black rook", rook.String())
+	knight, err := s.state.Board().At(boardtest.NewSquare("A1"))
+	s.NoError(err)
+	s.Equal("black knight", knight.String())
+	s.Len(s.state.Player(color.White).Captures(), 1)
+	s.Len(s.state.Player(color.Black).Captures(), 1)
+	s.Equal(s.state.Player(color.Black), s.state.CurrentPlayer())
+	s.Equal(12, s.state.TurnNumber())
+
+	exported, err := s.state.Setup()
+	s.NoError(err)
+	s.Equal(setup, exported)
+
+	err = s.state.RevertTurn()
+	s.ErrorIs(err, mess.ErrNoTurnToRevert)
+}
+
+func (s *StateSuite) TestSetupHashMatchesPlacedPieces() {
+	rookType := Rook(s.T())
+	s.state.AddPieceType(rookType)
+	err := mess.NewPiece(rookType, s.state.Player(color.White)).PlaceOn(s.state.Board(), boardtest.NewSquare("C3"))
+	s.NoError(err)
+
+	board, err := mess.NewPieceBoard(8, 8)
+	s.NoError(err)
+	state := mess.NewState(board)
+	state.AddPieceType(rookType)
+	err = state.LoadSetup("8/8/8/8/8/2R5/8/8 white - 0")
+	s.NoError(err)
+
+	s.Equal(s.state.Hash(), state.Hash())
+}
+
+func (s *StateSuite) TestSetupInvalid() {
+	s.state.AddPieceType(Rook(s.T()))
+	tests := []struct {
+		name  string
+		setup string
+	}{
+		{"TooFewFields", "8/8/8/8/8/8/8/8 white -"},
+		{"TooFewRanks", "8/8/8/8/8/8/8 white - 0"},
+		{"TooManyFiles", "9/8/8/8/8/8/8/8 white - 0"},
+		{"TooFewFiles", "7/8/8/8/8/8/8/8 white - 0"},
+		{"UnknownPiece", "Q7/8/8/8/8/8/8/8 white - 0"},
+		{"UnknownColor", "8/8/8/8/8/8/8/8 pink - 0"},
+		{"NoSuchPlayer", "8/8/8/8/8/8/8/8 red - 0"},
+		{"UnknownPieceInHand", "8/8/8/8/8/8/8/8 white Q 0"},
+		{"NegativeTurnNumber", "8/8/8/8/8/8/8/8 white - -1"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := s.state.LoadSetup(tt.setup)
+			s.Error(err)
+			s.Empty(s.state.Board().AllPieces())
+		})
+	}
+}
+
+func (s *StateSuite) TestSetupAmbiguousCodes() {
+	s.state.AddPieceType(King(s.T()))
+	s.state.AddPieceType(Knight(s.T()))
+
+	_, err := s.state.Setup()
+	s.ErrorContains(err, "ambiguous")
+}
+
 func TestStateSuite(t *testing.T) {
 	suite.Run(t, new(StateSuite))
 }
@@ -0,0 +1,68 @@
+package mess
+
+import (
+	"fmt"
+	"reflect"
+
+	"golang.org/x/exp/maps"
+)
+
+// DeclareVariable declares a game-level variable with the given initial value:
+// a bool, an int or a string. The variable keeps the type of the initial value.
+func (s *State) DeclareVariable(name string, initial any) error {
+	if err := validateVariable(initial); err != nil {
+		return fmt.Errorf("variable %q: %w", name, err)
+	} else if _, ok := s.variables[name]; ok {
+		return fmt.Errorf("variable %q already declared", name)
+	}
+	s.variables[name] = initial
+	s.zobrist.hash ^= variableKey(name, initial)
+	return nil
+}
+
+func (s *State) Variable(name string) (any, bool) {
+	value, ok := s.variables[name]
+	return value, ok
+}
+
+func (s *State) Variables() map[string]any {
+	return maps.Clone(s.variables)
+}
+
+// SetVariable sets the value of a declared variable. The change is recorded,
+// so that it can be undone.
+func (s *State) SetVariable(name string, value any) error {
+	old, ok := s.variables[name]
+	if !ok {
+		return fmt.Errorf("variable %q not declared", name)
+	} else if reflect.TypeOf(old) != reflect.TypeOf(value) {
+		return fmt.Errorf("variable %q: value %v is not of type %T", name, value, old)
+	}
+	s.setVariable(name, value)
+	return nil
+}
+
+func (s *State) setVariable(name string, value any) {
+	old := s.variables[name]
+	s.variables[name] = value
+	s.board.Notify(VariableSet{
+		Name: name,
+		From: old,
+		To:   value,
+	})
+}
+
+type VariableSet struct {
+	Name string
+	From any
+	To   any
+}
+
+func validateVariable(value any) error {
+	switch value.(type) {
+	case bool, int, string:
+		return nil
+	default:
+		return fmt.Errorf("value %v is neither a bool, an int nor a string", value)
+	}
+}
@@ -0,0 +1,121 @@
+package mess
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/color"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Zone is a named set of squares, e.g. a promotion zone or a palace. Some of
+// the squares can belong to the zone only for a given player.
+type Zone struct {
+	name          string
+	squares       []board.Square
+	playerSquares map[color.Color][]board.Square
+}
+
+// NewZone creates a zone consisting of the squares common to all the players
+// and the squares belonging to the zone only for the given players.
+func NewZone(name string, squares []board.Square, playerSquares map[color.Color][]board.Square) *Zone {
+	if playerSquares == nil {
+		playerSquares = make(map[color.Color][]board.Square)
+	}
+	return &Zone{
+		name:          name,
+		squares:       squares,
+		playerSquares: playerSquares,
+	}
+}
+
+func (z *Zone) Name() string {
+	return z.name
+}
+
+// CommonSquares returns the squares belonging to the zone for every player.
+func (z *Zone) CommonSquares() []board.Square {
+	return slices.Clone(z.squares)
+}
+
+// PlayerSquares returns the squares belonging to the zone only for some of
+// the players, by the color of the player.
+func (z *Zone) PlayerSquares() map[color.Color][]board.Square {
+	result := make(map[color.Color][]board.Square, len(z.playerSquares))
+	for color, squares := range z.playerSquares {
+		result[color] = slices.Clone(squares)
+	}
+	return result
+}
+
+// Squares returns the squares of the zone for the player of the given color,
+// or the squares of the zone for any of the players if the color is nil.
+func (z *Zone) Squares(color *color.Color) []board.Square {
+	squares := slices.Clone(z.squares)
+	if color != nil {
+		squares = append(squares, z.playerSquares[*color]...)
+	} else {
+		colors := maps.Keys(z.playerSquares)
+		slices.Sort(colors)
+		for _, color := range colors {
+			squares = append(squares, z.playerSquares[color]...)
+		}
+	}
+	result := make([]board.Square, 0, len(squares))
+	for _, square := range squares {
+		if !slices.Contains(result, square) {
+			result = append(result, square)
+		}
+	}
+	return result
+}
+
+// Contains checks if the square belongs to the zone for the player of the
+// given color, or for any of the players if the color is nil.
+func (z *Zone) Contains(square board.Square, color *color.Color) bool {
+	return slices.Contains(z.Squares(color), square)
+}
+
+// AddZone adds the zone to the state. The squares of the zone must lie on the
+// board and belong to the players of the game.
+func (s *State) AddZone(zone *Zone) error {
+	if _, ok := s.zones[zone.name]; ok {
+		return fmt.Errorf("zone %q already defined", zone.name)
+	}
+	for color, squares := range zone.playerSquares {
+		if _, ok := s.players[color]; !ok {
+			return fmt.Errorf("zone %q: no %s player in the game", zone.name, color)
+		}
+		if err := s.validateZoneSquares(squares); err != nil {
+			return fmt.Errorf("zone %q: %w", zone.name, err)
+		}
+	}
+	if err := s.validateZoneSquares(zone.squares); err != nil {
+		return fmt.Errorf("zone %q: %w", zone.name, err)
+	}
+	s.zones[zone.name] = zone
+	return nil
+}
+
+func (s *State) validateZoneSquares(squares []board.Square) error {
+	for _, square := range squares {
+		if !s.board.Contains(square) {
+			return fmt.Errorf("square %s is not on the board", square)
+		}
+	}
+	return nil
+}
+
+func (s *State) Zone(name string) (*Zone, bool) {
+	zone, ok := s.zones[name]
+	return zone, ok
+}
+
+// Zones returns all the zones sorted by name.
+func (s *State) Zones() []*Zone {
+	zones := maps.Values(s.zones)
+	slices.SortFunc(zones, func(a, b *Zone) int { return strings.Compare(a.name, b.name) })
+	return zones
+}
@@ -0,0 +1,197 @@
+package notation
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/rules"
+)
+
+const (
+	HeaderRules     = "Rules"
+	HeaderRulesHash = "RulesHash"
+	// HeaderSetup is the position the game started from, in the setup
+	// notation (see mess.State.Setup). Games without it start from the
+	// initial state of the rules.
+	HeaderSetup = "Setup"
+)
+
+// GameFile is a PGN-like record of a game. It consists of headers, describing
+// e.g. the rules the game was played with, followed by the notations of the
+// routes chosen in each turn:
+//
+//	[Rules "chess.hcl"]
+//	[RulesHash "3b1f..."]
+//
+//	1. E2-E4
+//	2. E7-E5
+//	; comments start with a semicolon
+//	3. E5-E6/queen
+type GameFile struct {
+	Headers []Header
+	Turns   []string
+}
+
+type Header struct {
+	Key   string
+	Value string
+}
+
+// NewGameFile records a game played with the given rules and routes.
+func NewGameFile(rulesFile *rules.File, routes []mess.Route) *GameFile {
+	result := &GameFile{}
+	result.SetHeader(HeaderRules, rulesFile.Filename)
+	result.SetHeader(HeaderRulesHash, RulesHash(rulesFile))
+	for _, route := range routes {
+		result.Turns = append(result.Turns, FormatRoute(route))
+	}
+	return result
+}
+
+// RulesHash returns a hex-encoded SHA-256 hash of the rules source.
+func RulesHash(rulesFile *rules.File) string {
+	hash := sha256.Sum256(rulesFile.Src)
+	return hex.EncodeToString(hash[:])
+}
+
+func (f *GameFile) Header(key string) (string, bool) {
+	for _, header := range f.Headers {
+		if header.Key == key {
+			return header.Value, true
+		}
+	}
+	return "", false
+}
+
+func (f *GameFile) SetHeader(key string, value string) {
+	for i, header := range f.Headers {
+		if header.Key == key {
+			f.Headers[i].Value = value
+			return
+		}
+	}
+	f.Headers = append(f.Headers, Header{Key: key, Value: value})
+}
+
+// CheckRules checks if the game was recorded with the given rules. Files
+// without a rules hash are assumed to match any rules.
+func (f *GameFile) CheckRules(rulesFile *rules.File) error {
+	hash, ok := f.Header(HeaderRulesHash)
+	if ok && hash != RulesHash(rulesFile) {
+		return ErrRulesMismatch
+	}
+	return nil
+}
+
+// NewGame decodes the rules and sets up the position the game started from.
+func (f *GameFile) NewGame(rulesFile *rules.File) (*mess.Game, error) {
+	if setup, ok := f.Header(HeaderSetup); ok {
+		return rules.DecodeRulesWithSetup(rulesFile, setup)
+	}
+	return rules.DecodeRules(rulesFile, true)
+}
+
+// Replay plays all the recorded turns in the given game and returns their
+// routes.
+func (f *GameFile) Replay(game *mess.Game) ([]mess.Route, error) {
+	routes := make([]mess.Route, 0, len(f.Turns))
+	for i, turn := range f.Turns {
+		route, err := ParseRoute(game.State, turn)
+		if err != nil {
+			return nil, fmt.Errorf("parsing turn %d: %w", i+1, err)
+		}
+		err = game.PlayTurn(route)
+		if err != nil {
+			return nil, fmt.Errorf("playing turn %d (%s): %w", i+1, turn, err)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func (f *GameFile) String() string {
+	var builder strings.Builder
+	for _, header := range f.Headers {
+		fmt.Fprintf(&builder, "[%s %s]\n", header.Key, strconv.Quote(header.Value))
+	}
+	if len(f.Headers) > 0 && len(f.Turns) > 0 {
+		builder.WriteString("\n")
+	}
+	for i, turn := range f.Turns {
+		fmt.Fprintf(&builder, "%d. %s\n", i+1, turn)
+	}
+	return builder.String()
+}
+
+var headerRegexp = regexp.MustCompile(`^\[(\w+)\s+(".*")\]$`)
+var turnNumberRegexp = regexp.MustCompile(`^(\d+)\.$`)
+
+// ParseGameFile parses a game file in the format described in GameFile.
+func ParseGameFile(src []byte) (*GameFile, error) {
+	result := &GameFile{}
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			if len(result.Turns) > 0 {
+				return nil, fmt.Errorf("line %d: header after turns", lineNumber)
+			}
+			header, err := parseHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			result.Headers = append(result.Headers, header)
+		default:
+			err := result.parseTurns(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseHeader(line string) (Header, error) {
+	match := headerRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return Header{}, fmt.Errorf("malformed header %q", line)
+	}
+	value, err := strconv.Unquote(match[2])
+	if err != nil {
+		return Header{}, fmt.Errorf("parsing header %q value: %w", match[1], err)
+	}
+	return Header{Key: match[1], Value: value}, nil
+}
+
+func (f *GameFile) parseTurns(line string) error {
+	for _, token := range strings.Fields(line) {
+		match := turnNumberRegexp.FindStringSubmatch(token)
+		if match == nil {
+			f.Turns = append(f.Turns, token)
+			continue
+		}
+		number, err := strconv.Atoi(match[1])
+		if err != nil {
+			return fmt.Errorf("parsing turn number: %w", err)
+		}
+		if number != len(f.Turns)+1 {
+			return fmt.Errorf("expected turn number %d, got %d", len(f.Turns)+1, number)
+		}
+	}
+	return nil
+}
+
+var ErrRulesMismatch = fmt.Errorf("game was recorded with different rules")
@@ -0,0 +1,172 @@
+package notation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/board/boardtest"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chessRulesFile = "../../rules/chess.hcl"
+
+func TestRouteRoundTrip(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(chessRulesFile, true)
+	require.NoError(t, err)
+	queen, err := game.GetPieceType("queen")
+	require.NoError(t, err)
+
+	tests := []struct {
+		route mess.Route
+		text  string
+	}{
+		{
+			route: mess.Route{mess.MoveOption{SquareVec: mess.SquareVec{
+				From: boardtest.NewSquare("E2"),
+				To:   boardtest.NewSquare("E4"),
+			}}},
+			text: "E2-E4",
+		},
+		{
+			route: mess.Route{
+				mess.SquareOption{Square: boardtest.NewSquare("A8")},
+				mess.PieceTypeOption{PieceType: queen},
+				mess.UnitOption{},
+			},
+			text: "A8/queen/*",
+		},
+		{
+			route: mess.Route{},
+			text:  "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.text, notation.FormatRoute(tt.route))
+
+			parsed, err := notation.ParseRoute(game.State, tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.route, parsed)
+		})
+	}
+}
+
+func TestParseUnknownPieceType(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(chessRulesFile, true)
+	require.NoError(t, err)
+
+	_, err = notation.ParseRoute(game.State, "E2-E4/dragon")
+
+	assert.Error(t, err)
+}
+
+func TestGameFileRoundTrip(t *testing.T) {
+	rulesFile := chessRules(t)
+	game, err := rules.DecodeRules(rulesFile, true)
+	require.NoError(t, err)
+	routes := playRoutes(t, game, "E2-E4", "E7-E5", "G1-F3")
+
+	file := notation.NewGameFile(rulesFile, routes)
+	file.SetHeader("Event", "test \"game\"")
+	parsed, err := notation.ParseGameFile([]byte(file.String()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, file, parsed)
+	assert.Equal(t, []string{"E2-E4", "E7-E5", "G1-F3"}, parsed.Turns)
+	assert.NoError(t, parsed.CheckRules(rulesFile))
+}
+
+func TestGameFileReplay(t *testing.T) {
+	rulesFile := chessRules(t)
+	src := `
+[Rules "chess.hcl"]
+
+; the opening
+1. E2-E4 2. E7-E5
+3. G1-F3
+`
+	file, err := notation.ParseGameFile([]byte(src))
+	require.NoError(t, err)
+	game, err := rules.DecodeRules(rulesFile, true)
+	require.NoError(t, err)
+
+	routes, err := file.Replay(game)
+
+	assert.NoError(t, err)
+	assert.Len(t, routes, 3)
+	assert.Equal(t, 3, game.TurnNumber())
+	knight, err := game.Board().At(boardtest.NewSquare("F3"))
+	assert.NoError(t, err)
+	assert.Equal(t, "knight", knight.Type().Name())
+}
+
+func TestGameFileReplayFromSetup(t *testing.T) {
+	rulesFile := chessRules(t)
+	src := `
+[Rules "chess.hcl"]
+[Setup "4k3/8/8/8/8/8/4P3/4K3 black - 10"]
+
+1. E8-D8
+2. E2-E4
+`
+	file, err := notation.ParseGameFile([]byte(src))
+	require.NoError(t, err)
+	game, err := file.NewGame(rulesFile)
+	require.NoError(t, err)
+
+	_, err = file.Replay(game)
+
+	assert.NoError(t, err)
+	setup, err := game.Setup()
+	assert.NoError(t, err)
+	assert.Equal(t, "3k4/8/8/8/4P3/8/8/4K3 black - 12", setup)
+}
+
+func TestGameFileReplayInvalidTurn(t *testing.T) {
+	rulesFile := chessRules(t)
+	file, err := notation.ParseGameFile([]byte("1. E2-E5"))
+	require.NoError(t, err)
+	game, err := rules.DecodeRules(rulesFile, true)
+	require.NoError(t, err)
+
+	_, err = file.Replay(game)
+
+	assert.Error(t, err)
+}
+
+func TestGameFileWrongTurnNumber(t *testing.T) {
+	_, err := notation.ParseGameFile([]byte("1. E2-E4 3. E7-E5"))
+
+	assert.Error(t, err)
+}
+
+func TestGameFileRulesMismatch(t *testing.T) {
+	file := notation.NewGameFile(&rules.File{Src: []byte("other"), Filename: "chess.hcl"}, nil)
+
+	err := file.CheckRules(chessRules(t))
+
+	assert.ErrorIs(t, err, notation.ErrRulesMismatch)
+}
+
+func chessRules(t *testing.T) *rules.File {
+	t.Helper()
+	src, err := os.ReadFile(chessRulesFile)
+	require.NoError(t, err)
+	return &rules.File{Src: src, Filename: "chess.hcl"}
+}
+
+func playRoutes(t *testing.T, game *mess.Game, turns ...string) []mess.Route {
+	t.Helper()
+	routes := make([]mess.Route, 0, len(turns))
+	for _, turn := range turns {
+		route, err := notation.ParseRoute(game.State, turn)
+		require.NoError(t, err)
+		require.NoError(t, game.PlayTurn(route))
+		routes = append(routes, route)
+	}
+	return routes
+}
@@ -0,0 +1,95 @@
+package notation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/mess"
+)
+
+const (
+	// optionSeparator separates options of a single route, e.g. "E7-E8/queen".
+	optionSeparator = "/"
+	moveSeparator   = "-"
+	unitOption      = "*"
+	// emptyRoute denotes a turn played without choosing any option.
+	emptyRoute = "-"
+)
+
+// FormatOption converts an option to its text notation:
+//   - move: "E2-E4",
+//   - square: "E4",
+//   - piece type: its name, e.g. "queen",
+//   - unit: "*".
+func FormatOption(option mess.Option) string {
+	switch o := option.(type) {
+	case mess.MoveOption:
+		return o.SquareVec.From.String() + moveSeparator + o.SquareVec.To.String()
+	case mess.SquareOption:
+		return o.Square.String()
+	case mess.PieceTypeOption:
+		return o.PieceType.Name()
+	case mess.UnitOption:
+		return unitOption
+	default:
+		panic(fmt.Errorf("unknown option type %T", option))
+	}
+}
+
+// FormatRoute converts a route to its text notation, i.e. the notations of its
+// options separated by slashes.
+func FormatRoute(route mess.Route) string {
+	if len(route) == 0 {
+		return emptyRoute
+	}
+	options := make([]string, len(route))
+	for i, option := range route {
+		options[i] = FormatOption(option)
+	}
+	return strings.Join(options, optionSeparator)
+}
+
+// ParseOption parses the text notation of an option. Piece types are looked up
+// in the given state.
+func ParseOption(state *mess.State, text string) (mess.Option, error) {
+	if text == unitOption {
+		return mess.UnitOption{}, nil
+	}
+	if from, to, ok := strings.Cut(text, moveSeparator); ok {
+		fromSquare, err := board.NewSquare(from)
+		if err != nil {
+			return nil, fmt.Errorf("parsing move source: %w", err)
+		}
+		toSquare, err := board.NewSquare(to)
+		if err != nil {
+			return nil, fmt.Errorf("parsing move destination: %w", err)
+		}
+		return mess.MoveOption{SquareVec: mess.SquareVec{From: fromSquare, To: toSquare}}, nil
+	}
+	if square, err := board.NewSquare(text); err == nil {
+		return mess.SquareOption{Square: square}, nil
+	}
+	pieceType, err := state.GetPieceType(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing option %q: %w", text, err)
+	}
+	return mess.PieceTypeOption{PieceType: pieceType}, nil
+}
+
+// ParseRoute parses the text notation of a route.
+func ParseRoute(state *mess.State, text string) (mess.Route, error) {
+	if text == emptyRoute {
+		return mess.Route{}, nil
+	}
+	parts := strings.Split(text, optionSeparator)
+	result := make(mess.Route, 0, len(parts))
+	for _, part := range parts {
+		option, err := ParseOption(state, part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, option)
+	}
+	return result, nil
+}
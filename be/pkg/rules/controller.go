@@ -11,6 +11,7 @@ import (
 	"github.com/jostrzol/mess/pkg/rules/ctymess"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 type controller struct {
@@ -35,6 +36,7 @@ func (c *controller) Resolution(state *mess.State) mess.Resolution {
 		return mess.Resolution{}
 	}
 
+	reasonCty, resultCty := popAttr(resultCty, "reason")
 	var result struct {
 		DidEnd         bool      `cty:"did_end"`
 		WinnerColorCty cty.Value `cty:"winner"`
@@ -44,12 +46,14 @@ func (c *controller) Resolution(state *mess.State) mess.Resolution {
 		return mess.Resolution{}
 	}
 
-	if !result.DidEnd || result.WinnerColorCty.IsNull() {
+	if !result.DidEnd {
 		// check if the current player can move - if not it's a stalemate
 		if len(state.ValidMoves()) == 0 {
-			result.DidEnd = true
+			return mess.Resolution{DidEnd: true, Reason: mess.Stalemate}
 		}
-		return mess.Resolution{DidEnd: result.DidEnd}
+		return c.drawResolution(state)
+	} else if result.WinnerColorCty.IsNull() {
+		return mess.Resolution{DidEnd: true, Reason: parseEndReason(reasonCty)}
 	}
 
 	var winnerColor string
@@ -64,7 +68,96 @@ func (c *controller) Resolution(state *mess.State) mess.Resolution {
 		return mess.Resolution{}
 	}
 
-	return mess.Resolution{DidEnd: true, Winner: state.Player(color)}
+	return mess.Resolution{
+		DidEnd: true,
+		Winner: state.Player(color),
+		Reason: parseEndReason(reasonCty),
+	}
+}
+
+// drawResolution checks the draw thresholds declared in the rules.
+func (c *controller) drawResolution(state *mess.State) mess.Resolution {
+	draws := c.rules.Draws
+	if draws == nil {
+		return mess.Resolution{}
+	}
+	if draws.Repetitions != nil && state.RepetitionCount() >= *draws.Repetitions {
+		return mess.Resolution{DidEnd: true, Reason: mess.Repetition}
+	}
+	if draws.PliesWithoutProgress != nil && c.pliesWithoutProgress(state) >= *draws.PliesWithoutProgress {
+		return mess.Resolution{DidEnd: true, Reason: mess.NoProgress}
+	}
+	return mess.Resolution{}
+}
+
+// pliesWithoutProgress returns the number of turns played since the last
+// capture or move of any of the progress piece types.
+func (c *controller) pliesWithoutProgress(state *mess.State) int {
+	result := state.PliesSinceCapture()
+	for _, name := range c.rules.Draws.ProgressPieceTypes {
+		pieceType, err := state.GetPieceType(name)
+		if err != nil {
+			log.Printf("getting progress piece type: %v", err)
+			continue
+		}
+		plies := state.PliesSinceMove(pieceType)
+		if plies < result {
+			result = plies
+		}
+	}
+	return result
+}
+
+// popAttr returns the attribute of the object (or null if there is no such
+// attribute) and the object without the attribute.
+func popAttr(object cty.Value, name string) (cty.Value, cty.Value) {
+	if !object.Type().IsObjectType() || !object.Type().HasAttribute(name) {
+		return cty.NullVal(cty.String), object
+	}
+	attrs := object.AsValueMap()
+	attr := attrs[name]
+	delete(attrs, name)
+	return attr, cty.ObjectVal(attrs)
+}
+
+// parseEndReason parses the optional reason returned by the resolve
+// user-defined function. Defaults to mess.RuleDefined.
+func parseEndReason(reasonCty cty.Value) mess.EndReason {
+	if reasonCty.IsNull() {
+		return mess.RuleDefined
+	}
+	var reasonStr string
+	if err := gocty.FromCtyValue(reasonCty, &reasonStr); err != nil {
+		log.Printf("parsing resolve user-defined function's result: reason: %v", err)
+		return mess.RuleDefined
+	}
+	reason, err := mess.EndReasonString(reasonStr)
+	if err != nil || reason == mess.NotEnded {
+		log.Printf("parsing end reason: %q is not a valid end reason", reasonStr)
+		return mess.RuleDefined
+	}
+	return reason
+}
+
+func (c *controller) Evaluate(_ *mess.State, player *mess.Player) (float64, bool) {
+	evaluateFunc, ok := c.rules.Functions.CustomFuncs["evaluate"]
+	if !ok {
+		return 0, false
+	}
+
+	ctyState := c.refreshGameStateInContext()
+	resultCty, err := evaluateFunc.Call([]cty.Value{ctyState, ctymess.PlayerToCty(player)})
+	if err != nil {
+		log.Printf("calling evaluate user-defined function: %v", err)
+		return 0, false
+	}
+
+	var result float64
+	if err = gocty.FromCtyValue(resultCty, &result); err != nil {
+		log.Printf("parsing evaluate user-defined function's result: %v", err)
+		return 0, false
+	}
+	return result, true
 }
 
 func (c *controller) TurnChoice(state *mess.State) (*mess.Choice, error) {
@@ -107,6 +200,29 @@ func (c *controller) Turn(_ *mess.State, options []mess.Option) error {
 	return nil
 }
 
+func (c *controller) GetCustomFuncAsPositionExtra(name string) (mess.PositionExtraFunc, error) {
+	funcCty, ok := c.rules.Functions.CustomFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf("user function %q not found", name)
+	}
+
+	return func(state *mess.State) string {
+		ctyState := c.refreshGameStateInContext()
+		result, err := funcCty.Call([]cty.Value{ctyState})
+		if err != nil {
+			log.Printf("calling position extra function %q: %v", name, err)
+			return ""
+		}
+
+		extra, err := ctyjson.Marshal(result, result.Type())
+		if err != nil {
+			log.Printf("parsing position extra function %q result: %v", name, err)
+			return ""
+		}
+		return string(extra)
+	}, nil
+}
+
 func (c *controller) GetCustomFuncAsGenerator(name string) (mess.MoveGeneratorFunc, error) {
 	funcCty, ok := c.rules.Functions.CustomFuncs[name]
 	if !ok {
@@ -212,7 +328,12 @@ func (c *controller) GetStateValidators() ([]mess.StateValidator, error) {
 }
 
 func (c *controller) refreshGameStateInContext() cty.Value {
-	newState := ctymess.StateToCty(c.state)
+	// the plies since move are counted for each piece type, so only if used
+	var pliesSinceMove []*mess.PieceType
+	if c.rules.UsedAttrs["plies_since_move"] {
+		pliesSinceMove = c.state.PieceTypes()
+	}
+	newState := ctymess.StateToCty(c.state, pliesSinceMove)
 	c.ctx.Variables["game"] = newState
 	return newState
 }
@@ -3,9 +3,14 @@ package ctymess
 import "github.com/zclconf/go-cty/cty"
 
 var Game = cty.Object(map[string]cty.Type{
-	"players":        cty.Map(Player),
-	"current_player": Player,
-	"record":         Record,
+	"players":             cty.Map(Player),
+	"current_player":      Player,
+	"turn_order":          cty.List(cty.String),
+	"record":              Record,
+	"repetitions":         cty.Number,
+	"plies_since_capture": cty.Number,
+	"plies_since_move":    cty.Map(cty.Number),
+	"variables":           cty.DynamicPseudoType,
 })
 
 var Player = cty.Object(map[string]cty.Type{
@@ -18,9 +23,11 @@ var Player = cty.Object(map[string]cty.Type{
 var Offset = cty.Tuple([]cty.Type{cty.Number, cty.Number})
 
 var Piece = cty.Object(map[string]cty.Type{
-	"type":   cty.String,
-	"color":  cty.String,
-	"square": cty.String,
+	"type":     cty.String,
+	"color":    cty.String,
+	"square":   cty.String,
+	"flags":    cty.Map(cty.Bool),
+	"counters": cty.Map(cty.Number),
 })
 
 var Record = cty.List(MoveGroup)
@@ -60,8 +67,11 @@ var Choice = cty.DynamicPseudoType
 var Coords = Offset
 
 var Board = cty.Object(map[string]cty.Type{
-	"width":  cty.Number,
-	"height": cty.Number,
+	"width":            cty.Number,
+	"height":           cty.Number,
+	"disabled_squares": cty.List(cty.String),
+	"topology":         cty.String,
+	"directions":       cty.List(Offset),
 })
 
 var PieceType = cty.Object(map[string]cty.Type{
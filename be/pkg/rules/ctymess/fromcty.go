@@ -83,6 +83,42 @@ func PieceFromCty(state *mess.State, value cty.Value) (*mess.Piece, error) {
 	return piece, nil
 }
 
+// PropertyFromCty converts the value of a piece property to either a bool or
+// an int.
+func PropertyFromCty(value cty.Value) (any, error) {
+	if value.IsNull() {
+		return nil, fmt.Errorf("property value is null")
+	}
+	switch value.Type() {
+	case cty.Bool:
+		return value.True(), nil
+	case cty.Number:
+		var result int
+		if err := gocty.FromCtyValue(value, &result); err != nil {
+			return nil, fmt.Errorf("parsing counter: %w", err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("property value must be either a bool or a number, got %v", value.Type().FriendlyName())
+	}
+}
+
+// VariableFromCty converts the value of a game variable to a bool, an int or
+// a string.
+func VariableFromCty(value cty.Value) (any, error) {
+	if value.IsNull() {
+		return nil, fmt.Errorf("variable value is null")
+	}
+	switch value.Type() {
+	case cty.String:
+		return value.AsString(), nil
+	case cty.Bool, cty.Number:
+		return PropertyFromCty(value)
+	default:
+		return nil, fmt.Errorf("variable value must be either a bool, a number or a string, got %v", value.Type().FriendlyName())
+	}
+}
+
 func PieceTypeFromCty(state *mess.State, value cty.Value) (*mess.PieceType, error) {
 	var err error
 	var pieceTypeName string
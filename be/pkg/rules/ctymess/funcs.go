@@ -249,8 +249,8 @@ var StateMissingFunc = function.New(&function.Spec{
 func GetSquareRelativeFunc(state *mess.State) function.Function {
 	return function.New(&function.Spec{
 		Description: joinText(
-			"Gets the square offset by a given relative position,",
-			"or null if the board doesn't contain the square",
+			"Gets the square offset by a given relative position according to the",
+			"board's topology, or null if the board doesn't contain the square",
 		),
 		Params: []function.Parameter{
 			{
@@ -276,8 +276,8 @@ func GetSquareRelativeFunc(state *mess.State) function.Function {
 				return cty.DynamicVal, fmt.Errorf("argument 'offset': %w", err)
 			}
 
-			result := square.Offset(offset)
-			if !state.Board().Contains(result) {
+			result, ok := state.Board().Offset(square, offset)
+			if !ok {
 				return cty.NullVal(cty.String), nil
 			}
 			return SquareToCty(result), nil
@@ -482,9 +482,12 @@ func CaptureFunc(state *mess.State) function.Function {
 				return cty.DynamicVal, fmt.Errorf("given piece not found")
 			}
 
-			opponent := state.OpponentTo(piece.Owner())
+			capturer := state.CurrentPlayer()
+			if capturer == piece.Owner() {
+				capturer = state.OpponentTo(piece.Owner())
+			}
 
-			if err = piece.GetCapturedBy(opponent); err != nil {
+			if err = piece.GetCapturedBy(capturer); err != nil {
 				return cty.DynamicVal, fmt.Errorf("capturing %v: %w", piece, err)
 			}
 
@@ -586,6 +589,226 @@ func ConvertAndReleaseFunc(state *mess.State) function.Function {
 	})
 }
 
+func SetPiecePropFunc(state *mess.State) function.Function {
+	return function.New(&function.Spec{
+		Description: "Sets the custom property of the given piece to a bool (a flag) or a number (a counter)",
+		Params: []function.Parameter{
+			{
+				Name:             "piece",
+				Type:             Piece,
+				AllowDynamicType: true,
+			},
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+			{
+				Name:             "value",
+				Type:             cty.DynamicPseudoType,
+				AllowDynamicType: true,
+			},
+		},
+		Type: function.StaticReturnType(cty.EmptyTuple),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			var piece *mess.Piece
+			var value any
+			var err error
+			if piece, err = PieceFromCty(state, args[0]); err != nil {
+				return cty.DynamicVal, fmt.Errorf("argument 'piece': %w", err)
+			} else if piece == nil {
+				return cty.DynamicVal, fmt.Errorf("given piece not found")
+			}
+			if value, err = PropertyFromCty(args[2]); err != nil {
+				return cty.DynamicVal, fmt.Errorf("argument 'value': %w", err)
+			}
+
+			if err = piece.SetProperty(args[1].AsString(), value); err != nil {
+				return cty.DynamicVal, fmt.Errorf("setting property of %v: %w", piece, err)
+			}
+
+			return cty.EmptyTupleVal, nil
+		},
+	})
+}
+
+func GetPiecePropFunc(state *mess.State) function.Function {
+	return function.New(&function.Spec{
+		Description: "Gets the custom property of the given piece or the default value if it is not set",
+		Params: []function.Parameter{
+			{
+				Name:             "piece",
+				Type:             Piece,
+				AllowDynamicType: true,
+			},
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+			{
+				Name:             "default",
+				Type:             cty.DynamicPseudoType,
+				AllowDynamicType: true,
+				AllowNull:        true,
+			},
+		},
+		Type: function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			var piece *mess.Piece
+			var err error
+			if piece, err = PieceFromCty(state, args[0]); err != nil {
+				return cty.DynamicVal, fmt.Errorf("argument 'piece': %w", err)
+			} else if piece == nil {
+				return cty.DynamicVal, fmt.Errorf("given piece not found")
+			}
+
+			value, ok := piece.Property(args[1].AsString())
+			if !ok {
+				return args[2], nil
+			}
+			return PropertyToCty(value), nil
+		},
+	})
+}
+
+func SetVariableFunc(state *mess.State) function.Function {
+	return function.New(&function.Spec{
+		Description: "Sets the game variable of the given name",
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+			{
+				Name:             "value",
+				Type:             cty.DynamicPseudoType,
+				AllowDynamicType: true,
+			},
+		},
+		Type: function.StaticReturnType(cty.EmptyTuple),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			var value any
+			var err error
+			if value, err = VariableFromCty(args[1]); err != nil {
+				return cty.DynamicVal, fmt.Errorf("argument 'value': %w", err)
+			}
+
+			if err = state.SetVariable(args[0].AsString(), value); err != nil {
+				return cty.DynamicVal, fmt.Errorf("setting variable: %w", err)
+			}
+
+			return cty.EmptyTupleVal, nil
+		},
+	})
+}
+
+func GetVariableFunc(state *mess.State) function.Function {
+	return function.New(&function.Spec{
+		Description: "Gets the current value of the game variable of the given name",
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			name := args[0].AsString()
+			value, ok := state.Variable(name)
+			if !ok {
+				return cty.DynamicVal, fmt.Errorf("variable %q not declared", name)
+			}
+			return VariableToCty(value), nil
+		},
+	})
+}
+
+func InZoneFunc(state *mess.State) function.Function {
+	return function.New(&function.Spec{
+		Description: joinText(
+			"Checks if the square belongs to the zone of the given name for the player",
+			"of the given color, or for any of the players if the color is omitted",
+		),
+		Params: []function.Parameter{
+			{
+				Name: "square",
+				Type: cty.String,
+			},
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+		},
+		VarParam: &function.Parameter{
+			Name: "color",
+			Type: cty.String,
+		},
+		Type: function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			square, err := SquareFromCty(args[0])
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("argument 'square': %w", err)
+			}
+			zone, color, err := zoneArgs(state, args[1:])
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+			return cty.BoolVal(zone.Contains(square, color)), nil
+		},
+	})
+}
+
+func ZoneSquaresFunc(state *mess.State) function.Function {
+	return function.New(&function.Spec{
+		Description: joinText(
+			"Gets the squares of the zone of the given name for the player of the given",
+			"color, or for any of the players if the color is omitted",
+		),
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+		},
+		VarParam: &function.Parameter{
+			Name: "color",
+			Type: cty.String,
+		},
+		Type: function.StaticReturnType(cty.List(cty.String)),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			zone, color, err := zoneArgs(state, args)
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+			squares := make([]cty.Value, 0)
+			for _, square := range zone.Squares(color) {
+				squares = append(squares, SquareToCty(square))
+			}
+			return listOrEmpty(cty.String, squares), nil
+		},
+	})
+}
+
+// zoneArgs decodes the zone name and the optional color.
+func zoneArgs(state *mess.State, args []cty.Value) (*mess.Zone, *color.Color, error) {
+	name := args[0].AsString()
+	zone, ok := state.Zone(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("zone %q not defined", name)
+	}
+	switch len(args) {
+	case 1:
+		return zone, nil, nil
+	case 2:
+		color, err := ColorFromCty(args[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("argument 'color': %w", err)
+		}
+		return zone, color, nil
+	default:
+		return nil, nil, fmt.Errorf("expected at most 1 color, got %d", len(args)-1)
+	}
+}
+
 func MakeMoveFunc(state *mess.State) function.Function {
 	return function.New(&function.Spec{
 		Description: "Perform the given move",
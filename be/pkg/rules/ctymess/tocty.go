@@ -8,18 +8,51 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
-func StateToCty(state *mess.State) cty.Value {
+// StateToCty converts the state. The plies since move are given only for the
+// given piece types.
+func StateToCty(state *mess.State, pliesSinceMoveTypes []*mess.PieceType) cty.Value {
 	players := make(map[string]cty.Value, len(state.Players()))
+	turnOrder := make([]cty.Value, 0, len(state.Players()))
 	for _, player := range state.Players() {
 		players[player.Color().String()] = PlayerToCty(player)
+		turnOrder = append(turnOrder, cty.StringVal(player.Color().String()))
+	}
+	pliesSinceMove := make(map[string]cty.Value, len(pliesSinceMoveTypes))
+	for _, pieceType := range pliesSinceMoveTypes {
+		pliesSinceMove[pieceType.Name()] = cty.NumberIntVal(int64(state.PliesSinceMove(pieceType)))
 	}
 	return cty.ObjectVal(map[string]cty.Value{
-		"players":        cty.MapVal(players),
-		"current_player": players[state.CurrentPlayer().Color().String()],
-		"record":         RecordToCty(state.Record()),
+		"players":             cty.MapVal(players),
+		"current_player":      players[state.CurrentPlayer().Color().String()],
+		"turn_order":          cty.ListVal(turnOrder),
+		"record":              RecordToCty(state.Record()),
+		"repetitions":         cty.NumberIntVal(int64(state.RepetitionCount())),
+		"plies_since_capture": cty.NumberIntVal(int64(state.PliesSinceCapture())),
+		"plies_since_move":    mapOrEmpty(cty.Number, pliesSinceMove),
+		"variables":           VariablesToCty(state.Variables()),
 	})
 }
 
+func VariablesToCty(variables map[string]any) cty.Value {
+	if len(variables) == 0 {
+		return cty.EmptyObjectVal
+	}
+	result := make(map[string]cty.Value, len(variables))
+	for name, value := range variables {
+		result[name] = VariableToCty(value)
+	}
+	return cty.ObjectVal(result)
+}
+
+// VariableToCty converts the value of a game variable to either a bool,
+// a number or a string.
+func VariableToCty(value any) cty.Value {
+	if v, ok := value.(string); ok {
+		return cty.StringVal(v)
+	}
+	return PropertyToCty(value)
+}
+
 func PlayerToCty(player *mess.Player) cty.Value {
 	pieces := make([]cty.Value, 0, len(player.Pieces()))
 	for _, piece := range player.Pieces() {
@@ -38,14 +71,47 @@ func PlayerToCty(player *mess.Player) cty.Value {
 	})
 }
 
+var noFlags = cty.MapValEmpty(cty.Bool)
+var noCounters = cty.MapValEmpty(cty.Number)
+
 func PieceToCty(piece *mess.Piece) cty.Value {
+	flags, counters := noFlags, noCounters
+	if props := piece.Properties(); len(props) != 0 {
+		flagsMap := make(map[string]cty.Value)
+		countersMap := make(map[string]cty.Value)
+		for name, value := range props {
+			switch v := value.(type) {
+			case bool:
+				flagsMap[name] = cty.BoolVal(v)
+			case int:
+				countersMap[name] = cty.NumberIntVal(int64(v))
+			}
+		}
+		flags = mapOrEmpty(cty.Bool, flagsMap)
+		counters = mapOrEmpty(cty.Number, countersMap)
+	}
 	return cty.ObjectVal(map[string]cty.Value{
-		"type":   cty.StringVal(piece.Type().Name()),
-		"color":  cty.StringVal(piece.Color().String()),
-		"square": cty.StringVal(piece.Square().String()),
+		"type":     cty.StringVal(piece.Type().Name()),
+		"color":    cty.StringVal(piece.Color().String()),
+		"square":   cty.StringVal(piece.Square().String()),
+		"flags":    flags,
+		"counters": counters,
 	})
 }
 
+// PropertyToCty converts the value of a piece property to either a bool or
+// a number.
+func PropertyToCty(value any) cty.Value {
+	switch v := value.(type) {
+	case bool:
+		return cty.BoolVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	default:
+		panic(fmt.Errorf("unsupported property value %v", value))
+	}
+}
+
 func SquareToCty(square board.Square) cty.Value {
 	return cty.StringVal(square.String())
 }
@@ -111,9 +177,20 @@ func SquareVecToCty(vec mess.SquareVec) cty.Value {
 
 func BoardToCty(board *mess.PieceBoard) cty.Value {
 	width, height := board.Size()
+	disabled := make([]cty.Value, 0)
+	for _, square := range board.DisabledSquares() {
+		disabled = append(disabled, SquareToCty(square))
+	}
+	directions := make([]cty.Value, 0)
+	for _, offset := range board.Topology().Directions() {
+		directions = append(directions, OffsetToCty(offset))
+	}
 	return cty.ObjectVal(map[string]cty.Value{
-		"width":  cty.NumberIntVal(int64(width)),
-		"height": cty.NumberIntVal(int64(height)),
+		"width":            cty.NumberIntVal(int64(width)),
+		"height":           cty.NumberIntVal(int64(height)),
+		"disabled_squares": listOrEmpty(cty.String, disabled),
+		"topology":         cty.StringVal(board.Topology().String()),
+		"directions":       cty.ListVal(directions),
 	})
 }
 
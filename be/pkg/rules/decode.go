@@ -15,17 +15,36 @@ import (
 
 type rules struct {
 	Board           boardRules           `hcl:"board,block"`
+	Players         *playersRules        `hcl:"players,block"`
 	PieceTypes      pieceTypesRules      `hcl:"piece_types,block"`
 	InitialState    initialStateRules    `hcl:"initial_state,block"`
 	StateValidators *stateValidatorRules `hcl:"state_validators,block"`
 	Turn            *turnRules           `hcl:"turn,block"`
+	Draws           *drawsRules          `hcl:"draws,block"`
+	Variables       *variablesRules      `hcl:"variables,block"`
+	Zones           *zonesRules          `hcl:"zones,block"`
 	Assets          *cty.Value           `hcl:"assets"`
 	Functions       callbackFunctionsRules
+	// UsedAttrs are the names of all the attributes traversed in the rules,
+	// e.g. "repetitions" for game.repetitions.
+	UsedAttrs map[string]bool
 }
 
 type boardRules struct {
-	Height uint `hcl:"height"`
-	Width  uint `hcl:"width"`
+	Height          uint     `hcl:"height"`
+	Width           uint     `hcl:"width"`
+	Topology        string   `hcl:"topology,optional"`
+	Mask            []string `hcl:"mask,optional"`
+	DisabledSquares []string `hcl:"disabled_squares,optional"`
+}
+
+type playersRules struct {
+	Players []playerRules `hcl:"player,block"`
+}
+
+type playerRules struct {
+	Color            string `hcl:"color,label"`
+	ForwardDirection []int  `hcl:"forward_direction"`
 }
 
 type pieceTypesRules struct {
@@ -39,12 +58,19 @@ type pieceTypeRules struct {
 }
 
 type presentations struct {
-	White *presentation `hcl:"white,block"`
-	Black *presentation `hcl:"black,block"`
+	White  *presentation `hcl:"white,block"`
+	Black  *presentation `hcl:"black,block"`
+	Red    *presentation `hcl:"red,block"`
+	Green  *presentation `hcl:"green,block"`
+	Blue   *presentation `hcl:"blue,block"`
+	Yellow *presentation `hcl:"yellow,block"`
+	Orange *presentation `hcl:"orange,block"`
+	Purple *presentation `hcl:"purple,block"`
 }
 
 type presentation struct {
 	Symbol *string `hcl:"symbol"`
+	Code   *string `hcl:"code"`
 	Icon   *string `hcl:"icon"`
 	Rotate *bool   `hcl:"rotate"`
 }
@@ -56,8 +82,9 @@ type motionRules struct {
 }
 
 type initialStateRules struct {
-	WhitePieces map[string]string `hcl:"white_pieces"`
-	BlackPieces map[string]string `hcl:"black_pieces"`
+	Pieces      map[string]map[string]string `hcl:"pieces,optional"`
+	WhitePieces map[string]string            `hcl:"white_pieces,optional"`
+	BlackPieces map[string]string            `hcl:"black_pieces,optional"`
 }
 
 type constantsRules struct {
@@ -78,6 +105,27 @@ type turnRules struct {
 	ActionName         string `hcl:"action,optional"`
 }
 
+type drawsRules struct {
+	Repetitions          *int     `hcl:"repetitions"`
+	PliesWithoutProgress *int     `hcl:"plies_without_progress"`
+	ProgressPieceTypes   []string `hcl:"progress_piece_types,optional"`
+	PositionExtraName    string   `hcl:"position_extra,optional"`
+}
+
+type variablesRules struct {
+	Variables hcl.Attributes `hcl:",remain"`
+}
+
+type zonesRules struct {
+	Zones []zoneRules `hcl:"zone,block"`
+}
+
+type zoneRules struct {
+	Name          string         `hcl:"zone_name,label"`
+	Squares       []string       `hcl:"squares,optional"`
+	PlayerSquares hcl.Attributes `hcl:",remain"`
+}
+
 type callbackFunctionsRules struct {
 	ResolutionFunc  function.Function            `mapstructure:"resolve"`
 	CustomFuncs     map[string]function.Function `mapstructure:",remain"`
@@ -103,7 +151,7 @@ func decodeRules(src []byte, filename string, ctx *hcl.EvalContext) (*rules, err
 	tmpDiags = mergeWithStd(ctx.Variables, userConstants, "variable")
 	diags = diags.Extend(tmpDiags)
 
-	rules := &rules{}
+	rules := &rules{UsedAttrs: usedAttrs(file.Body.(*hclsyntax.Body))}
 	tmpDiags = gohcl.DecodeBody(body, ctx, rules)
 	diags = diags.Extend(tmpDiags)
 
@@ -130,6 +178,54 @@ func decodeRules(src []byte, filename string, ctx *hcl.EvalContext) (*rules, err
 	return rules, nil
 }
 
+// usedAttrs returns the names of all the attributes traversed in the body.
+// The game state can be passed to functions under any name, so the names are
+// collected regardless of the traversed value.
+func usedAttrs(body *hclsyntax.Body) map[string]bool {
+	result := make(map[string]bool)
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		var traversal hcl.Traversal
+		switch expr := node.(type) {
+		case *hclsyntax.ScopeTraversalExpr:
+			traversal = expr.Traversal
+		case *hclsyntax.RelativeTraversalExpr:
+			traversal = expr.Traversal
+		}
+		for _, step := range traversal {
+			if attr, ok := step.(hcl.TraverseAttr); ok {
+				result[attr.Name] = true
+			}
+		}
+		return nil
+	})
+	return result
+}
+
+// decodePlayers decodes only the players block, without evaluating the rest
+// of the rules.
+func decodePlayers(src []byte, filename string) (*playersRules, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	content, _, diags := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "players"}},
+	})
+	if diags.HasErrors() {
+		return nil, diags
+	} else if len(content.Blocks) == 0 {
+		return nil, nil
+	}
+
+	players := &playersRules{}
+	diags = gohcl.DecodeBody(content.Blocks[0].Body, nil, players)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return players, nil
+}
+
 func decodeUserFunctions(
 	body hcl.Body, ctx *hcl.EvalContext,
 ) (map[string]function.Function, hcl.Body, hcl.Diagnostics) {
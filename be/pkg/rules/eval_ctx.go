@@ -7,6 +7,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
 	"github.com/zclconf/go-cty/cty/function/stdlib"
+	"golang.org/x/exp/maps"
 )
 
 var InitialEvalContext = &hcl.EvalContext{
@@ -26,6 +27,7 @@ var InitialEvalContext = &hcl.EvalContext{
 		"slice":               stdlib.SliceFunc,
 		"abs":                 stdlib.AbsoluteFunc,
 		"contains":            stdlib.ContainsFunc,
+		"sort":                stdlib.SortFunc,
 		"format":              stdlib.FormatFunc,
 		"sum":                 ctymess.SumFunc,
 		"concat":              ctymess.ConcatFunc,
@@ -46,6 +48,12 @@ var InitialEvalContext = &hcl.EvalContext{
 		"place_new_piece":     ctymess.StateMissingFunc,
 		"convert_and_release": ctymess.StateMissingFunc,
 		"make_move":           ctymess.StateMissingFunc,
+		"set_piece_prop":      ctymess.StateMissingFunc,
+		"get_piece_prop":      ctymess.StateMissingFunc,
+		"set_variable":        ctymess.StateMissingFunc,
+		"get_variable":        ctymess.StateMissingFunc,
+		"in_zone":             ctymess.StateMissingFunc,
+		"zone_squares":        ctymess.StateMissingFunc,
 		"call":                ctymess.StateMissingFunc,
 		"cond_call":           ctymess.StateMissingFunc,
 	},
@@ -56,6 +64,15 @@ var InitialEvalContext = &hcl.EvalContext{
 	},
 }
 
+// newEvalContext copies the initial context, so that the functions and the
+// constants defined in one rules file do not leak into the other games.
+func newEvalContext() *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Functions: maps.Clone(InitialEvalContext.Functions),
+		Variables: maps.Clone(InitialEvalContext.Variables),
+	}
+}
+
 func initializeContext(ctx *hcl.EvalContext, game *mess.Game) {
 	ctx.Functions["get_square_relative"] = ctymess.GetSquareRelativeFunc(game.State)
 	ctx.Functions["piece_at"] = ctymess.PieceAtFunc(game.State)
@@ -67,10 +84,15 @@ func initializeContext(ctx *hcl.EvalContext, game *mess.Game) {
 	ctx.Functions["place_new_piece"] = ctymess.PlaceNewPieceFunc(game.State)
 	ctx.Functions["convert_and_release"] = ctymess.ConvertAndReleaseFunc(game.State)
 	ctx.Functions["make_move"] = ctymess.MakeMoveFunc(game.State)
+	ctx.Functions["set_piece_prop"] = ctymess.SetPiecePropFunc(game.State)
+	ctx.Functions["get_piece_prop"] = ctymess.GetPiecePropFunc(game.State)
+	ctx.Functions["set_variable"] = ctymess.SetVariableFunc(game.State)
+	ctx.Functions["get_variable"] = ctymess.GetVariableFunc(game.State)
+	ctx.Functions["in_zone"] = ctymess.InZoneFunc(game.State)
+	ctx.Functions["zone_squares"] = ctymess.ZoneSquaresFunc(game.State)
 	ctx.Functions["call"] = ctymess.CallFunc(ctx)
 	ctx.Functions["cond_call"] = ctymess.CondCallFunc(ctx)
 
-	ctx.Variables["game"] = ctymess.StateToCty(game.State)
 	ctx.Variables["piece_types"] = ctymess.PieceTypesToCty(game.PieceTypes())
 	ctx.Variables["board"] = ctymess.BoardToCty(game.State.Board())
 }
@@ -0,0 +1,335 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Lint checks the rules for mistakes, which would otherwise surface only while
+// playing the game: callbacks naming undefined functions or functions of
+// a wrong arity, initial pieces placed outside of the board or of undefined
+// types and presentation icons missing from the assets.
+func Lint(file *File) hcl.Diagnostics {
+	ctx := newEvalContext()
+
+	rules, err := decodeRules(file.Src, file.Filename, ctx)
+	if err != nil {
+		return errorToDiagnostics(err)
+	}
+	syntaxFile, diags := hclsyntax.ParseConfig(file.Src, file.Filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	l := &linter{
+		rules: rules,
+		ctx:   ctx,
+		body:  syntaxFile.Body.(*hclsyntax.Body),
+	}
+	l.lintCallbacks()
+	l.lintInitialState()
+	l.lintPresentations()
+	if l.diags.HasErrors() {
+		return l.diags
+	}
+
+	// the remaining errors can only be found by building the game
+	game, err := rules.toEmptyGameState(ctx)
+	if err == nil {
+		err = rules.placePieces(game.State)
+	}
+	if err != nil {
+		l.diags = l.diags.Extend(errorToDiagnostics(err))
+	}
+	return l.diags
+}
+
+func errorToDiagnostics(err error) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if errors.As(err, &diags) {
+		return diags
+	}
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid rules",
+		Detail:   err.Error(),
+	}}
+}
+
+type linter struct {
+	rules *rules
+	ctx   *hcl.EvalContext
+	body  *hclsyntax.Body
+	diags hcl.Diagnostics
+}
+
+func (l *linter) lintCallbacks() {
+	for _, pieceTypes := range blocksOfType(l.body, "piece_types") {
+		for _, pieceType := range blocksOfType(pieceTypes.Body, "piece_type") {
+			for _, motion := range blocksOfType(pieceType.Body, "motion") {
+				l.lintCallback(motion.Body, "generator", "motion generator", 2)
+				l.lintCallback(motion.Body, "choice", "motion choice", 3)
+				l.lintCallback(motion.Body, "action", "motion action", 4)
+			}
+		}
+	}
+	for _, turn := range blocksOfType(l.body, "turn") {
+		l.lintCallback(turn.Body, "choice", "turn choice", 0)
+		l.lintCallback(turn.Body, "action", "turn action", 1)
+	}
+	for _, draws := range blocksOfType(l.body, "draws") {
+		l.lintCallback(draws.Body, "position_extra", "position extra", 1)
+	}
+
+	if resolve, ok := l.ctx.Functions["resolve"]; !ok {
+		l.diags = l.diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing resolve function",
+			Detail:   `The function "resolve" deciding when the game ends must be defined.`,
+			Subject:  l.fileStart().Ptr(),
+		})
+	} else {
+		l.lintArity(resolve, "resolve", "resolve", 1, l.functionRange("resolve"))
+	}
+	if evaluate, ok := l.rules.Functions.CustomFuncs["evaluate"]; ok {
+		l.lintArity(evaluate, "evaluate", "evaluate", 2, l.functionRange("evaluate"))
+	}
+
+	for _, validators := range blocksOfType(l.body, "state_validators") {
+		for _, block := range validators.Body.Blocks {
+			if len(block.Labels) == 0 {
+				continue
+			}
+			name := block.Labels[0]
+			if validator, ok := l.rules.Functions.StateValidators[name]; ok {
+				l.lintArity(validator, name, "state validator", 1, block.DefRange())
+			}
+		}
+	}
+}
+
+// lintCallback checks the optional attribute naming a function called with the
+// given number of arguments.
+func (l *linter) lintCallback(body *hclsyntax.Body, attrName string, kind string, arity int) {
+	attr, ok := body.Attributes[attrName]
+	if !ok {
+		return
+	}
+	var name string
+	if diags := gohcl.DecodeExpression(attr.Expr, l.ctx, &name); diags.HasErrors() {
+		l.diags = l.diags.Extend(diags)
+		return
+	}
+	f, ok := l.rules.Functions.CustomFuncs[name]
+	if !ok {
+		l.diags = l.diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Undefined function",
+			Detail:   fmt.Sprintf("The %s function %q is not defined.", kind, name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return
+	}
+	l.lintArity(f, name, kind, arity, attr.Expr.Range())
+}
+
+func (l *linter) lintArity(f function.Function, name string, kind string, arity int, subject hcl.Range) {
+	params := len(f.Params())
+	if params == arity || (f.VarParam() != nil && params <= arity) {
+		return
+	}
+	l.diags = l.diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Wrong number of parameters",
+		Detail: fmt.Sprintf("The %s function %q is called with %d arguments, but it has %d parameters.",
+			kind, name, arity, params),
+		Subject: subject.Ptr(),
+	})
+}
+
+func (l *linter) functionRange(name string) hcl.Range {
+	for _, block := range l.body.Blocks {
+		isFunction := block.Type == "function" || block.Type == "composite_function"
+		if isFunction && len(block.Labels) > 0 && block.Labels[0] == name {
+			return block.DefRange()
+		}
+	}
+	return l.fileStart()
+}
+
+func (l *linter) fileStart() hcl.Range {
+	start := l.body.SrcRange.Start
+	return hcl.Range{Filename: l.body.SrcRange.Filename, Start: start, End: start}
+}
+
+func (l *linter) lintInitialState() {
+	initialStates := blocksOfType(l.body, "initial_state")
+	if len(initialStates) == 0 {
+		return
+	}
+	body := initialStates[0].Body
+
+	disabledSquares, err := l.rules.Board.disabledSquares()
+	if err != nil {
+		// reported while building the game
+		return
+	}
+	pieceTypes := make(map[string]bool, len(l.rules.PieceTypes.PieceTypes))
+	for _, pieceType := range l.rules.PieceTypes.PieceTypes {
+		pieceTypes[pieceType.Name] = true
+	}
+
+	lintPieces := func(expr hclsyntax.Expression, path []string, pieces map[string]string) {
+		squareStrs := maps.Keys(pieces)
+		slices.Sort(squareStrs)
+		for _, squareStr := range squareStrs {
+			subject := itemRange(expr, append(path, squareStr)...)
+			square, err := board.NewSquare(squareStr)
+			if err != nil {
+				l.diags = l.diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid square",
+					Detail:   fmt.Sprintf("Parsing square: %v.", err),
+					Subject:  subject.Ptr(),
+				})
+				continue
+			}
+			x, y := square.ToCoords()
+			isOnBoard := x < int(l.rules.Board.Width) && y < int(l.rules.Board.Height) &&
+				!slices.Contains(disabledSquares, square)
+			if !isOnBoard {
+				l.diags = l.diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Square outside of the board",
+					Detail:   fmt.Sprintf("The square %s is not on the board.", square),
+					Subject:  subject.Ptr(),
+				})
+			}
+			if pieceType := pieces[squareStr]; !pieceTypes[pieceType] {
+				l.diags = l.diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Undefined piece type",
+					Detail:   fmt.Sprintf("The piece type %q is not defined.", pieceType),
+					Subject:  subject.Ptr(),
+				})
+			}
+		}
+	}
+
+	initialState := l.rules.InitialState
+	if attr, ok := body.Attributes["pieces"]; ok {
+		colorStrs := maps.Keys(initialState.Pieces)
+		slices.Sort(colorStrs)
+		for _, colorStr := range colorStrs {
+			if _, err := color.ColorString(colorStr); err != nil {
+				subject := itemRange(attr.Expr, colorStr)
+				l.diags = l.diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid color",
+					Detail:   fmt.Sprintf("Parsing player color: %v.", err),
+					Subject:  subject.Ptr(),
+				})
+				continue
+			}
+			lintPieces(attr.Expr, []string{colorStr}, initialState.Pieces[colorStr])
+		}
+	}
+	if attr, ok := body.Attributes["white_pieces"]; ok {
+		lintPieces(attr.Expr, nil, initialState.WhitePieces)
+	}
+	if attr, ok := body.Attributes["black_pieces"]; ok {
+		lintPieces(attr.Expr, nil, initialState.BlackPieces)
+	}
+}
+
+func (l *linter) lintPresentations() {
+	assets := make(mess.Assets)
+	if l.rules.Assets != nil {
+		var err error
+		assets, err = decodeAssets(*l.rules.Assets)
+		if err != nil {
+			subject := l.fileStart()
+			if attr, ok := l.body.Attributes["assets"]; ok {
+				subject = attr.Expr.Range()
+			}
+			l.diags = l.diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid assets",
+				Detail:   fmt.Sprintf("%v.", err),
+				Subject:  subject.Ptr(),
+			})
+			return
+		}
+	}
+
+	for _, pieceTypes := range blocksOfType(l.body, "piece_types") {
+		for _, pieceType := range blocksOfType(pieceTypes.Body, "piece_type") {
+			for _, presentations := range blocksOfType(pieceType.Body, "presentation") {
+				for _, presentation := range presentations.Body.Blocks {
+					attr, ok := presentation.Body.Attributes["icon"]
+					if !ok {
+						continue
+					}
+					var icon string
+					if diags := gohcl.DecodeExpression(attr.Expr, l.ctx, &icon); diags.HasErrors() {
+						l.diags = l.diags.Extend(diags)
+						continue
+					}
+					if _, ok := assets[mess.NewAssetKey(icon)]; !ok {
+						l.diags = l.diags.Append(&hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Missing asset",
+							Detail:   fmt.Sprintf("The icon %q is not defined in the assets.", icon),
+							Subject:  attr.Expr.Range().Ptr(),
+						})
+					}
+				}
+			}
+		}
+	}
+}
+
+func blocksOfType(body *hclsyntax.Body, blockType string) []*hclsyntax.Block {
+	result := make([]*hclsyntax.Block, 0)
+	for _, block := range body.Blocks {
+		if block.Type == blockType {
+			result = append(result, block)
+		}
+	}
+	return result
+}
+
+// itemRange returns the range of the item of nested object constructors found
+// under the given keys. If the item cannot be found, e.g. because the object is
+// computed, returns the range of the innermost expression found.
+func itemRange(expr hclsyntax.Expression, keys ...string) hcl.Range {
+	for _, key := range keys {
+		object, ok := expr.(*hclsyntax.ObjectConsExpr)
+		if !ok {
+			break
+		}
+		found := false
+		for _, item := range object.Items {
+			keyValue, diags := item.KeyExpr.Value(nil)
+			if !diags.HasErrors() && keyValue.Type() == cty.String && keyValue.AsString() == key {
+				expr, found = item.ValueExpr, true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	return expr.Range()
+}
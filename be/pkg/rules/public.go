@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/mess"
 )
 
@@ -22,7 +23,7 @@ func DecodeRulesFromOs(filename string, placePieces bool) (*mess.Game, error) {
 }
 
 func DecodeRules(file *File, placePieces bool) (*mess.Game, error) {
-	ctx := InitialEvalContext
+	ctx := newEvalContext()
 
 	rules, err := decodeRules(file.Src, file.Filename, ctx)
 	if err != nil {
@@ -43,3 +44,40 @@ func DecodeRules(file *File, placePieces bool) (*mess.Game, error) {
 
 	return game, nil
 }
+
+// DecodeRulesWithSetup decodes the rules and starts the game from the position
+// given in the setup notation (see mess.State.Setup) instead of the initial
+// state.
+func DecodeRulesWithSetup(file *File, setup string) (*mess.Game, error) {
+	game, err := DecodeRules(file, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = game.LoadSetup(setup)
+	if err != nil {
+		return nil, fmt.Errorf("loading setup: %w", err)
+	}
+
+	return game, nil
+}
+
+// DecodePlayers returns the colors of the players defined in the rules file,
+// in their turn order.
+func DecodePlayers(file *File) ([]color.Color, error) {
+	playersRules, err := decodePlayers(file.Src, file.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("decoding rules: %w", err)
+	}
+
+	configs, err := playersRules.toPlayerConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("decoding players: %w", err)
+	}
+
+	colors := make([]color.Color, 0, len(configs))
+	for _, config := range configs {
+		colors = append(colors, config.Color)
+	}
+	return colors, nil
+}
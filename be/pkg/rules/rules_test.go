@@ -1,12 +1,465 @@
 package rules
 
 import (
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/board/boardtest"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/rules/ctymess"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestDecode(t *testing.T) {
 	_, err := DecodeRulesFromOs("../../rules/chess.hcl", true)
 	assert.NoError(t, err)
 }
+
+func TestEvaluate(t *testing.T) {
+	game, err := DecodeRulesFromOs("../../rules/chess.hcl", true)
+	assert.NoError(t, err)
+
+	score, ok := game.Evaluate(game.CurrentPlayer())
+
+	assert.True(t, ok)
+	assert.Zero(t, score)
+}
+
+func TestDecodePlayers(t *testing.T) {
+	tests := []struct {
+		filename string
+		colors   []color.Color
+	}{
+		{"../../rules/chess.hcl", []color.Color{color.White, color.Black}},
+		{"../../rules/halma_4.hcl", []color.Color{color.White, color.Red, color.Black, color.Blue}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			src, err := os.ReadFile(tt.filename)
+			assert.NoError(t, err)
+
+			colors, err := DecodePlayers(&File{Src: src, Filename: tt.filename})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.colors, colors)
+		})
+	}
+}
+
+func TestDecodePlayersInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no players":      `players {}`,
+		"unknown color":   `players { player "pink" { forward_direction = [0, 1] } }`,
+		"short direction": `players { player "red" { forward_direction = [1] } }`,
+		"duplicate": `players {
+			player "red" { forward_direction = [0, 1] }
+			player "red" { forward_direction = [0, -1] }
+		}`,
+	}
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodePlayers(&File{Src: []byte(src), Filename: "rules.hcl"})
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPieceProperties(t *testing.T) {
+	game, err := DecodeRulesFromOs("../../rules/chess.hcl", true)
+	require.NoError(t, err)
+	king, err := game.Board().At(boardtest.NewSquare("E1"))
+	require.NoError(t, err)
+	setProp := ctymess.SetPiecePropFunc(game.State)
+	getProp := ctymess.GetPiecePropFunc(game.State)
+
+	_, err = setProp.Call([]cty.Value{ctymess.PieceToCty(king), cty.StringVal("moved"), cty.True})
+	require.NoError(t, err)
+	_, err = setProp.Call([]cty.Value{ctymess.PieceToCty(king), cty.StringVal("checks"), cty.NumberIntVal(2)})
+	require.NoError(t, err)
+
+	kingCty := ctymess.PieceToCty(king)
+	assert.Equal(t, cty.MapVal(map[string]cty.Value{"moved": cty.True}), kingCty.GetAttr("flags"))
+	assert.Equal(t, cty.MapVal(map[string]cty.Value{"checks": cty.NumberIntVal(2)}), kingCty.GetAttr("counters"))
+	moved, err := getProp.Call([]cty.Value{kingCty, cty.StringVal("moved"), cty.False})
+	require.NoError(t, err)
+	assert.Equal(t, cty.True, moved)
+
+	game.UndoTurn()
+	assert.Empty(t, king.Properties())
+	moved, err = getProp.Call([]cty.Value{ctymess.PieceToCty(king), cty.StringVal("moved"), cty.False})
+	require.NoError(t, err)
+	assert.Equal(t, cty.False, moved)
+}
+
+func TestPiecePropertiesInvalid(t *testing.T) {
+	game, err := DecodeRulesFromOs("../../rules/chess.hcl", true)
+	require.NoError(t, err)
+	king, err := game.Board().At(boardtest.NewSquare("E1"))
+	require.NoError(t, err)
+	setProp := ctymess.SetPiecePropFunc(game.State)
+
+	for name, value := range map[string]cty.Value{
+		"string":   cty.StringVal("yes"),
+		"fraction": cty.NumberFloatVal(0.5),
+		"null":     cty.NullVal(cty.Bool),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := setProp.Call([]cty.Value{ctymess.PieceToCty(king), cty.StringVal("prop"), value})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestUsedAttrs(t *testing.T) {
+	src, err := os.ReadFile("../../rules/chess.hcl")
+	require.NoError(t, err)
+	src = append(src, []byte(`
+function "is_pawn_idle" {
+  params = [g]
+  result = g.plies_since_move["pawn"] > 10
+}`)...)
+
+	rules, err := decodeRules(src, "chess.hcl", newEvalContext())
+	require.NoError(t, err)
+
+	assert.True(t, rules.UsedAttrs["plies_since_move"])
+	assert.False(t, rules.UsedAttrs["repetitions"])
+}
+
+func TestVariables(t *testing.T) {
+	src, err := os.ReadFile("../../rules/chess.hcl")
+	require.NoError(t, err)
+	src = append(src, []byte(`
+variables {
+  checks  = 0
+  variant = "three-check"
+}`)...)
+	game, err := DecodeRules(&File{Src: src, Filename: "chess.hcl"}, true)
+	require.NoError(t, err)
+	setVariable := ctymess.SetVariableFunc(game.State)
+	getVariable := ctymess.GetVariableFunc(game.State)
+	initialHash := game.Hash()
+
+	assert.Equal(t, map[string]any{"checks": 0, "variant": "three-check"}, game.Variables())
+
+	_, err = setVariable.Call([]cty.Value{cty.StringVal("checks"), cty.NumberIntVal(1)})
+	require.NoError(t, err)
+	checks, err := getVariable.Call([]cty.Value{cty.StringVal("checks")})
+	require.NoError(t, err)
+	assert.Equal(t, cty.NumberIntVal(1), checks)
+	variables := ctymess.StateToCty(game.State, nil).GetAttr("variables")
+	assert.Equal(t, cty.NumberIntVal(1), variables.GetAttr("checks"))
+	assert.NotEqual(t, initialHash, game.Hash())
+
+	_, err = setVariable.Call([]cty.Value{cty.StringVal("checks"), cty.StringVal("many")})
+	assert.Error(t, err, "type mismatch")
+	_, err = setVariable.Call([]cty.Value{cty.StringVal("undeclared"), cty.NumberIntVal(1)})
+	assert.Error(t, err, "undeclared variable")
+
+	game.UndoTurn()
+	value, _ := game.Variable("checks")
+	assert.Equal(t, 0, value)
+	assert.Equal(t, initialHash, game.Hash())
+}
+
+func TestVariablesInvalid(t *testing.T) {
+	src, err := os.ReadFile("../../rules/chess.hcl")
+	require.NoError(t, err)
+	src = append(src, []byte(`
+variables {
+  squares = ["A1", "H1"]
+}`)...)
+
+	_, err = DecodeRules(&File{Src: src, Filename: "chess.hcl"}, true)
+
+	assert.Error(t, err)
+}
+
+func TestZones(t *testing.T) {
+	game, err := DecodeRulesFromOs("../../rules/dobutsu_shogi.hcl", true)
+	require.NoError(t, err)
+	inZone := ctymess.InZoneFunc(game.State)
+	zoneSquares := ctymess.ZoneSquaresFunc(game.State)
+	lastRank := cty.StringVal("last_rank")
+
+	inside, err := inZone.Call([]cty.Value{cty.StringVal("B4"), lastRank, cty.StringVal("white")})
+	require.NoError(t, err)
+	assert.Equal(t, cty.True, inside)
+	inside, err = inZone.Call([]cty.Value{cty.StringVal("B4"), lastRank, cty.StringVal("black")})
+	require.NoError(t, err)
+	assert.Equal(t, cty.False, inside)
+	inside, err = inZone.Call([]cty.Value{cty.StringVal("B1"), lastRank})
+	require.NoError(t, err)
+	assert.Equal(t, cty.True, inside)
+
+	squares, err := zoneSquares.Call([]cty.Value{lastRank, cty.StringVal("black")})
+	require.NoError(t, err)
+	assert.Equal(t, cty.ListVal([]cty.Value{
+		cty.StringVal("A1"), cty.StringVal("B1"), cty.StringVal("C1"),
+	}), squares)
+	squares, err = zoneSquares.Call([]cty.Value{lastRank})
+	require.NoError(t, err)
+	assert.Equal(t, 6, squares.LengthInt())
+
+	_, err = zoneSquares.Call([]cty.Value{cty.StringVal("palace")})
+	assert.Error(t, err, "undefined zone")
+}
+
+func TestZonesInvalid(t *testing.T) {
+	tests := map[string]string{
+		"square": `
+zones {
+  zone "camp" {
+    squares = ["I1"]
+  }
+}`,
+		"color": `
+zones {
+  zone "camp" {
+    purple = ["A1"]
+  }
+}`,
+		"duplicate": `
+zones {
+  zone "camp" {
+    squares = ["A1"]
+  }
+  zone "camp" {
+    squares = ["A2"]
+  }
+}`,
+	}
+	for name, zonesSrc := range tests {
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile("../../rules/chess.hcl")
+			require.NoError(t, err)
+			src = append(src, []byte(zonesSrc)...)
+
+			_, err = DecodeRules(&File{Src: src, Filename: "chess.hcl"}, true)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDisabledSquares(t *testing.T) {
+	tests := map[string]string{
+		"list": `board {
+  height = 8
+  width  = 8
+  disabled_squares = ["D4", "E5"]
+}`,
+		"mask": `board {
+  height = 8
+  width  = 8
+  mask = [
+    "........",
+    "........",
+    "........",
+    "....#...",
+    "...#....",
+    "........",
+    "........",
+    "........",
+  ]
+}`,
+	}
+	for name, boardSrc := range tests {
+		t.Run(name, func(t *testing.T) {
+			game, err := DecodeRules(chessWithBoard(t, boardSrc), true)
+			require.NoError(t, err)
+
+			disabled := []board.Square{boardtest.NewSquare("D4"), boardtest.NewSquare("E5")}
+			assert.Equal(t, disabled, game.Board().DisabledSquares())
+			assert.False(t, game.Board().Contains(boardtest.NewSquare("D4")))
+			assert.True(t, game.Board().Contains(boardtest.NewSquare("E4")))
+		})
+	}
+}
+
+func TestDisabledSquaresInvalid(t *testing.T) {
+	tests := map[string]string{
+		"initial piece": `board {
+  height           = 8
+  width            = 8
+  disabled_squares = ["E1"]
+}`,
+		"out of bounds": `board {
+  height           = 8
+  width            = 8
+  disabled_squares = ["I1"]
+}`,
+		"mask height": `board {
+  height = 8
+  width  = 8
+  mask   = ["........"]
+}`,
+		"mask width": `board {
+  height = 2
+  width  = 8
+  mask   = ["........", "..."]
+}`,
+		"mask character": `board {
+  height = 2
+  width  = 2
+  mask   = ["..", ".x"]
+}`,
+	}
+	for name, boardSrc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodeRules(chessWithBoard(t, boardSrc), true)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTopology(t *testing.T) {
+	game, err := DecodeRules(chessWithBoard(t, `board {
+  height   = 8
+  width    = 8
+  topology = "cylindrical"
+}`), true)
+	require.NoError(t, err)
+	getSquareRelative := ctymess.GetSquareRelativeFunc(game.State)
+
+	assert.Equal(t, board.Cylindrical, game.Board().Topology())
+	square, err := getSquareRelative.Call([]cty.Value{cty.StringVal("A1"), ctymess.OffsetToCty(board.Offset{X: -1, Y: 0})})
+	require.NoError(t, err)
+	assert.Equal(t, cty.StringVal("H1"), square)
+	square, err = getSquareRelative.Call([]cty.Value{cty.StringVal("A8"), ctymess.OffsetToCty(board.Offset{X: 0, Y: 1})})
+	require.NoError(t, err)
+	assert.True(t, square.IsNull())
+	directions := ctymess.BoardToCty(game.Board()).GetAttr("directions")
+	assert.Equal(t, 8, directions.LengthInt())
+}
+
+func TestTopologyInvalid(t *testing.T) {
+	_, err := DecodeRules(chessWithBoard(t, `board {
+  height   = 8
+  width    = 8
+  topology = "spherical"
+}`), true)
+
+	assert.Error(t, err)
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		filename string
+		setup    string
+	}{
+		{"../../rules/chess.hcl", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR white - 0"},
+		{"../../rules/dobutsu_shogi.hcl", "gle/1c1/1C1/ELG white - 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			game, err := DecodeRulesFromOs(tt.filename, true)
+			require.NoError(t, err)
+
+			setup, err := game.Setup()
+			require.NoError(t, err)
+			assert.Equal(t, tt.setup, setup)
+		})
+	}
+}
+
+func TestDecodeRulesWithSetup(t *testing.T) {
+	src, err := os.ReadFile("../../rules/dobutsu_shogi.hcl")
+	require.NoError(t, err)
+	file := &File{Src: src, Filename: "dobutsu_shogi.hcl"}
+
+	game, err := DecodeRulesWithSetup(file, "gl1/1e1/1CG/EL1 black Cc 6")
+	require.NoError(t, err)
+
+	assert.Equal(t, color.Black, game.CurrentPlayer().Color())
+	assert.Equal(t, 6, game.TurnNumber())
+	assert.Len(t, game.Player(color.White).Captures(), 1)
+	assert.Len(t, game.Player(color.Black).Captures(), 1)
+	piece, err := game.Board().At(boardtest.NewSquare("B3"))
+	require.NoError(t, err)
+	assert.Equal(t, "black elephant", piece.String())
+	assert.NotEmpty(t, game.ValidMoves())
+
+	setup, err := game.Setup()
+	require.NoError(t, err)
+	assert.Equal(t, "gl1/1e1/1CG/EL1 black Cc 6", setup)
+}
+
+func TestDecodeRulesWithSetupInvalid(t *testing.T) {
+	src, err := os.ReadFile("../../rules/dobutsu_shogi.hcl")
+	require.NoError(t, err)
+	file := &File{Src: src, Filename: "dobutsu_shogi.hcl"}
+
+	_, err = DecodeRulesWithSetup(file, "gle/1c1/1C1/ELG white - 0 extra")
+	assert.Error(t, err)
+}
+
+func TestLint(t *testing.T) {
+	for _, filename := range []string{"chess.hcl", "dobutsu_shogi.hcl", "halma.hcl", "halma_4.hcl"} {
+		t.Run(filename, func(t *testing.T) {
+			src, err := os.ReadFile("../../rules/" + filename)
+			require.NoError(t, err)
+
+			diags := Lint(&File{Src: src, Filename: filename})
+
+			assert.Empty(t, diags)
+		})
+	}
+}
+
+func TestLintProblems(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		summary string
+	}{
+		{"undefined generator", `generator = "motion_hook"`, `generator = "motion_hok"`, "Undefined function"},
+		{"action arity", `action = "turn"`, `action = "turn_choose_move"`, "Wrong number of parameters"},
+		{"square off board", `A1 = "rook"`, `A9 = "rook"`, "Square outside of the board"},
+		{"undefined piece type", `B1 = "knight"`, `B1 = "knigt"`, "Undefined piece type"},
+		{"missing icon", `"/piece_types/king.svg"`, `"/piece_types/kong.svg"`, "Missing asset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := os.ReadFile("../../rules/chess.hcl")
+			require.NoError(t, err)
+			require.Contains(t, string(src), tt.old)
+			src = []byte(strings.Replace(string(src), tt.old, tt.new, 1))
+
+			diags := Lint(&File{Src: src, Filename: "chess.hcl"})
+
+			require.Len(t, diags, 1, diags.Error())
+			assert.Equal(t, tt.summary, diags[0].Summary)
+			line := strings.Count(string(src[:strings.Index(string(src), tt.new)]), "\n") + 1
+			assert.Equal(t, line, diags[0].Subject.Start.Line)
+		})
+	}
+}
+
+func TestLintMissingResolve(t *testing.T) {
+	src, err := os.ReadFile("../../rules/chess.hcl")
+	require.NoError(t, err)
+	src = []byte(strings.Replace(string(src), `composite_function "resolve"`, `composite_function "resolv"`, 1))
+
+	diags := Lint(&File{Src: src, Filename: "chess.hcl"})
+
+	require.Len(t, diags, 1, diags.Error())
+	assert.Equal(t, "Missing resolve function", diags[0].Summary)
+}
+
+func chessWithBoard(t *testing.T, boardSrc string) *File {
+	t.Helper()
+	src, err := os.ReadFile("../../rules/chess.hcl")
+	require.NoError(t, err)
+	chessBoard := "board {\n  height = 8\n  width  = 8\n}"
+	require.Contains(t, string(src), chessBoard)
+	src = []byte(strings.Replace(string(src), chessBoard, boardSrc, 1))
+	return &File{Src: src, Filename: "chess.hcl"}
+}
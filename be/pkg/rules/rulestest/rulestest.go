@@ -0,0 +1,301 @@
+// Package rulestest runs declarative tests of rules files. The tests are kept
+// in a sidecar file next to the rules, e.g. chess_test.hcl next to chess.hcl:
+//
+//	test "castling" {
+//	  position = {
+//	    white = { E1 = "king", A1 = "rook", H1 = "rook" }
+//	    black = { E8 = "king" }
+//	  }
+//	  play         = ["A1-A2", "E8-D8"]
+//	  expect_moves = { E1 = ["D1", "D2", "E2", "F1", "F2", "G1"] }
+//	}
+//
+// Instead of the position, the test can start from a setup, i.e. a position in
+// the setup notation (see mess.State.Setup):
+//
+//	setup = "7k/5K2/6Q1/8/8/8/8/8 black - 0"
+//
+// Each test starts from the given position, or from the initial state of the
+// rules if there is none, plays the given routes and checks the expectations:
+//   - expect_moves: the destinations of the moves of the pieces on the given
+//     squares,
+//   - expect_pieces: the pieces standing on the given squares,
+//   - expect_empty: the squares without any piece,
+//   - expect_resolution: the block with the attributes "did_end", "winner"
+//     and "reason" describing the end of the game.
+package rulestest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/rules"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type Case struct {
+	Name string `hcl:"name,label"`
+	// Position maps the colors of the players to their pieces by square.
+	Position map[string]map[string]string `hcl:"position,optional"`
+	// Turn is the color of the player to move in the position.
+	Turn string `hcl:"turn,optional"`
+	// Setup is the position in the setup notation, used instead of Position
+	// and Turn.
+	Setup            string                       `hcl:"setup,optional"`
+	Play             []string                     `hcl:"play,optional"`
+	ExpectMoves      map[string][]string          `hcl:"expect_moves,optional"`
+	ExpectPieces     map[string]map[string]string `hcl:"expect_pieces,optional"`
+	ExpectEmpty      []string                     `hcl:"expect_empty,optional"`
+	ExpectResolution *Resolution                  `hcl:"expect_resolution,block"`
+}
+
+type Resolution struct {
+	DidEnd bool    `hcl:"did_end"`
+	Winner *string `hcl:"winner"`
+	Reason *string `hcl:"reason"`
+}
+
+type testsFile struct {
+	Tests []*Case `hcl:"test,block"`
+}
+
+// TestsFilename returns the name of the sidecar file with the tests of the
+// given rules file.
+func TestsFilename(rulesFilename string) string {
+	return strings.TrimSuffix(rulesFilename, ".hcl") + "_test.hcl"
+}
+
+func DecodeTests(src []byte, filename string) ([]*Case, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	tests := &testsFile{}
+	diags = gohcl.DecodeBody(file.Body, nil, tests)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return tests.Tests, nil
+}
+
+func DecodeTestsFromOs(filename string) ([]*Case, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening tests file: %w", err)
+	}
+	return DecodeTests(src, filename)
+}
+
+// Run runs the test case against the rules. Returns the failed expectations.
+func (c *Case) Run(rulesFile *rules.File) []error {
+	var game *mess.Game
+	var err error
+	if c.Setup != "" {
+		if c.Position != nil || c.Turn != "" {
+			return []error{fmt.Errorf("setup cannot be combined with position or turn")}
+		}
+		game, err = rules.DecodeRulesWithSetup(rulesFile, c.Setup)
+		if err != nil {
+			return []error{err}
+		}
+	} else {
+		game, err = rules.DecodeRules(rulesFile, c.Position == nil)
+		if err != nil {
+			return []error{err}
+		}
+		if err = setUp(game, c.Position, c.Turn); err != nil {
+			return []error{fmt.Errorf("setting up the position: %w", err)}
+		}
+	}
+
+	for _, routeStr := range c.Play {
+		route, err := notation.ParseRoute(game.State, routeStr)
+		if err != nil {
+			return []error{fmt.Errorf("playing %q: %w", routeStr, err)}
+		}
+		if err = game.PlayTurn(route); err != nil {
+			return []error{fmt.Errorf("playing %q: %w", routeStr, err)}
+		}
+	}
+
+	errs := make([]error, 0)
+	errs = append(errs, checkMoves(game, c.ExpectMoves)...)
+	errs = append(errs, checkPieces(game, c.ExpectPieces)...)
+	errs = append(errs, checkEmpty(game, c.ExpectEmpty)...)
+	if c.ExpectResolution != nil {
+		errs = append(errs, checkResolution(game, c.ExpectResolution)...)
+	}
+	return errs
+}
+
+// RunAll runs the tests from the sidecar file of the rules file as subtests.
+func RunAll(t *testing.T, rulesFilename string) {
+	t.Helper()
+	src, err := os.ReadFile(rulesFilename)
+	if err != nil {
+		t.Fatalf("opening rules file: %v", err)
+	}
+	rulesFile := &rules.File{Src: src, Filename: rulesFilename}
+
+	tests, err := DecodeTestsFromOs(TestsFilename(rulesFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			for _, err := range tt.Run(rulesFile) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func setUp(game *mess.Game, position map[string]map[string]string, turn string) error {
+	for colorStr, pieces := range position {
+		player, err := parsePlayer(game, colorStr)
+		if err != nil {
+			return err
+		}
+		for squareStr, pieceTypeName := range pieces {
+			square, err := board.NewSquare(squareStr)
+			if err != nil {
+				return err
+			}
+			pieceType, err := game.GetPieceType(pieceTypeName)
+			if err != nil {
+				return err
+			}
+			if err = mess.NewPiece(pieceType, player).PlaceOn(game.Board(), square); err != nil {
+				return err
+			}
+		}
+	}
+
+	if turn == "" {
+		return nil
+	}
+	player, err := parsePlayer(game, turn)
+	if err != nil {
+		return err
+	}
+	for i := 0; game.CurrentPlayer() != player; i++ {
+		if i == len(game.Players()) {
+			return fmt.Errorf("player %s never moves", player.Color())
+		}
+		game.EndTurn()
+	}
+	return nil
+}
+
+func parsePlayer(game *mess.Game, colorStr string) (*mess.Player, error) {
+	playerColor, err := color.ColorString(colorStr)
+	if err != nil {
+		return nil, err
+	}
+	player := game.Player(playerColor)
+	if player == nil {
+		return nil, fmt.Errorf("no %s player in the game", playerColor)
+	}
+	return player, nil
+}
+
+func checkMoves(game *mess.Game, expectMoves map[string][]string) []error {
+	destinations := make(map[string][]string)
+	for _, moveGroup := range game.ValidMoves() {
+		from := moveGroup.From.String()
+		to := moveGroup.To.String()
+		if !slices.Contains(destinations[from], to) {
+			destinations[from] = append(destinations[from], to)
+		}
+	}
+
+	errs := make([]error, 0)
+	for _, from := range sortedKeys(expectMoves) {
+		expected := slices.Clone(expectMoves[from])
+		actual := destinations[from]
+		slices.Sort(expected)
+		slices.Sort(actual)
+		if !slices.Equal(expected, actual) {
+			errs = append(errs, fmt.Errorf("moves from %s: expected %v, got %v", from, expected, actual))
+		}
+	}
+	return errs
+}
+
+func checkPieces(game *mess.Game, expectPieces map[string]map[string]string) []error {
+	errs := make([]error, 0)
+	for _, colorStr := range sortedKeys(expectPieces) {
+		pieces := expectPieces[colorStr]
+		for _, squareStr := range sortedKeys(pieces) {
+			expected := fmt.Sprintf("%s %s", colorStr, pieces[squareStr])
+			piece, err := pieceAt(game, squareStr)
+			if err != nil {
+				errs = append(errs, err)
+			} else if piece == nil {
+				errs = append(errs, fmt.Errorf("piece on %s: expected %s, got none", squareStr, expected))
+			} else if actual := fmt.Sprintf("%s %s", piece.Color(), piece.Type().Name()); actual != expected {
+				errs = append(errs, fmt.Errorf("piece on %s: expected %s, got %s", squareStr, expected, actual))
+			}
+		}
+	}
+	return errs
+}
+
+func checkEmpty(game *mess.Game, expectEmpty []string) []error {
+	errs := make([]error, 0)
+	for _, squareStr := range expectEmpty {
+		piece, err := pieceAt(game, squareStr)
+		if err != nil {
+			errs = append(errs, err)
+		} else if piece != nil {
+			errs = append(errs, fmt.Errorf("piece on %s: expected none, got %s %s",
+				squareStr, piece.Color(), piece.Type().Name()))
+		}
+	}
+	return errs
+}
+
+func pieceAt(game *mess.Game, squareStr string) (*mess.Piece, error) {
+	square, err := board.NewSquare(squareStr)
+	if err != nil {
+		return nil, err
+	}
+	return game.Board().At(square)
+}
+
+func checkResolution(game *mess.Game, expected *Resolution) []error {
+	resolution := game.Resolution()
+	errs := make([]error, 0)
+	if resolution.DidEnd != expected.DidEnd {
+		errs = append(errs, fmt.Errorf("game ended: expected %v, got %v", expected.DidEnd, resolution.DidEnd))
+	}
+	if expected.Winner != nil {
+		winner := "none"
+		if resolution.Winner != nil {
+			winner = resolution.Winner.Color().String()
+		}
+		if winner != *expected.Winner {
+			errs = append(errs, fmt.Errorf("winner: expected %s, got %s", *expected.Winner, winner))
+		}
+	}
+	if expected.Reason != nil && resolution.Reason.String() != *expected.Reason {
+		errs = append(errs, fmt.Errorf("end reason: expected %s, got %s", *expected.Reason, resolution.Reason))
+	}
+	return errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
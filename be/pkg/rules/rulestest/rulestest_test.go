@@ -0,0 +1,60 @@
+package rulestest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testsSrc = `
+test "knight" {
+  position = {
+    white = { E1 = "king", B1 = "knight" }
+    black = { E8 = "king" }
+  }
+  expect_moves = { B1 = ["A3", "C3", "D2"] }
+  expect_resolution {
+    did_end = false
+  }
+}
+
+test "wrong" {
+  position = {
+    white = { E1 = "king", B1 = "knight" }
+    black = { E8 = "king" }
+  }
+  play          = ["B1-C3"]
+  expect_moves  = { E8 = ["D8"] }
+  expect_pieces = { white = { C3 = "bishop" } }
+  expect_empty  = ["C3"]
+  expect_resolution {
+    did_end = true
+    winner  = "white"
+  }
+}
+`
+
+func TestTestsFilename(t *testing.T) {
+	assert.Equal(t, "rules/chess_test.hcl", TestsFilename("rules/chess.hcl"))
+}
+
+func TestRun(t *testing.T) {
+	src, err := os.ReadFile("../../../rules/chess.hcl")
+	require.NoError(t, err)
+	rulesFile := &rules.File{Src: src, Filename: "chess.hcl"}
+
+	tests, err := DecodeTests([]byte(testsSrc), "chess_test.hcl")
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+
+	assert.Empty(t, tests[0].Run(rulesFile))
+	assert.Len(t, tests[1].Run(rulesFile), 5)
+}
+
+func TestDecodeTestsInvalid(t *testing.T) {
+	_, err := DecodeTests([]byte(`test "no_label" { unknown = 1 }`), "rules_test.hcl")
+	assert.Error(t, err)
+}
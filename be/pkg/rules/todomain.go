@@ -9,19 +9,37 @@ import (
 	"unicode/utf8"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/rules/ctymess"
 	"github.com/zclconf/go-cty/cty"
 )
 
 func (c *rules) toEmptyGameState(ctx *hcl.EvalContext) (*mess.Game, error) {
-	brd, err := mess.NewPieceBoard(int(c.Board.Width), int(c.Board.Height))
+	topology, err := c.Board.topology()
+	if err != nil {
+		return nil, fmt.Errorf("decoding board: %w", err)
+	}
+	brd, err := mess.NewPieceBoardWithTopology(int(c.Board.Width), int(c.Board.Height), topology)
 	if err != nil {
 		return nil, fmt.Errorf("creating new board: %w", err)
 	}
+	disabledSquares, err := c.Board.disabledSquares()
+	if err != nil {
+		return nil, fmt.Errorf("decoding board: %w", err)
+	}
+	if err = brd.Disable(disabledSquares...); err != nil {
+		return nil, fmt.Errorf("decoding board: %w", err)
+	}
 
-	state := mess.NewState(brd)
+	playerConfigs, err := c.Players.toPlayerConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("decoding players: %w", err)
+	}
+
+	state := mess.NewStateWithPlayers(brd, playerConfigs)
 	controller := newController(state, ctx, c)
 
 	game := mess.NewGame(state, controller)
@@ -35,13 +53,43 @@ func (c *rules) toEmptyGameState(ctx *hcl.EvalContext) (*mess.Game, error) {
 	}
 
 	for _, pieceTypeRules := range c.PieceTypes.PieceTypes {
-		pieceType, err := decodePieceType(controller, pieceTypeRules)
+		pieceType, err := decodePieceType(controller, pieceTypeRules, playerConfigs)
 		if err != nil {
 			return nil, fmt.Errorf("decoding piece type %q: %v", pieceTypeRules.Name, err)
 		}
 		state.AddPieceType(pieceType)
 	}
 
+	if c.Draws != nil {
+		err = c.Draws.validate(state)
+		if err != nil {
+			return nil, fmt.Errorf("decoding draws: %w", err)
+		}
+		// the extra is only needed to tell the repeated positions apart
+		usesRepetitions := c.Draws.Repetitions != nil || c.UsedAttrs["repetitions"]
+		if c.Draws.PositionExtraName != "" && usesRepetitions {
+			positionExtra, err := controller.GetCustomFuncAsPositionExtra(c.Draws.PositionExtraName)
+			if err != nil {
+				return nil, fmt.Errorf("decoding draws: %w", err)
+			}
+			state.SetPositionExtra(positionExtra)
+		}
+	}
+
+	if c.Variables != nil {
+		err = c.Variables.declare(state, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("decoding variables: %w", err)
+		}
+	}
+
+	if c.Zones != nil {
+		err = c.Zones.add(state, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("decoding zones: %w", err)
+		}
+	}
+
 	if c.Assets != nil {
 		assets, err := decodeAssets(*c.Assets)
 		if err != nil {
@@ -51,11 +99,134 @@ func (c *rules) toEmptyGameState(ctx *hcl.EvalContext) (*mess.Game, error) {
 	}
 
 	initializeContext(ctx, game)
+	controller.refreshGameStateInContext()
 	return game, nil
 }
 
-func decodePieceType(controller *controller, pieceTypeRules pieceTypeRules) (*mess.PieceType, error) {
+func (b *boardRules) topology() (board.Topology, error) {
+	if b.Topology == "" {
+		return board.Rectangular, nil
+	}
+	return board.TopologyString(b.Topology)
+}
+
+// disabledSquares returns the squares disabled either explicitly or by the
+// mask. The mask lists the ranks from the top one, with '.' marking the
+// squares of the board and '#' marking the holes.
+func (b *boardRules) disabledSquares() ([]board.Square, error) {
+	result, err := parseSquares(b.DisabledSquares)
+	if err != nil {
+		return nil, fmt.Errorf("disabled squares: %w", err)
+	}
+
+	if b.Mask == nil {
+		return result, nil
+	} else if len(b.Mask) != int(b.Height) {
+		return nil, fmt.Errorf("mask has %d rows, but the board height is %d", len(b.Mask), b.Height)
+	}
+	for i, row := range b.Mask {
+		if utf8.RuneCountInString(row) != int(b.Width) {
+			return nil, fmt.Errorf("mask row %d has %d squares, but the board width is %d",
+				i+1, utf8.RuneCountInString(row), b.Width)
+		}
+		y := int(b.Height) - 1 - i
+		for x, char := range []rune(row) {
+			switch char {
+			case '.':
+			case '#':
+				result = append(result, board.SquareFromCoords(x, y))
+			default:
+				return nil, fmt.Errorf("mask row %d: invalid character %q (expected '.' or '#')", i+1, char)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (d *drawsRules) validate(state *mess.State) error {
+	if d.Repetitions != nil && *d.Repetitions < 2 {
+		return fmt.Errorf("repetitions must be at least 2, got %d", *d.Repetitions)
+	}
+	if d.PliesWithoutProgress != nil && *d.PliesWithoutProgress <= 0 {
+		return fmt.Errorf("plies_without_progress must be positive, got %d", *d.PliesWithoutProgress)
+	}
+	for _, name := range d.ProgressPieceTypes {
+		if _, err := state.GetPieceType(name); err != nil {
+			return fmt.Errorf("progress piece types: %w", err)
+		}
+	}
+	return nil
+}
+
+func (v *variablesRules) declare(state *mess.State, ctx *hcl.EvalContext) error {
+	for name, attr := range v.Variables {
+		value, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return diags
+		}
+		initial, err := ctymess.VariableFromCty(value)
+		if err != nil {
+			return fmt.Errorf("variable %q: %w", name, err)
+		}
+		if err = state.DeclareVariable(name, initial); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add adds the zones to the state. Apart from the squares common to all the
+// players, a zone can list the squares belonging to it only for a given player
+// in an attribute named after the player's color.
+func (z *zonesRules) add(state *mess.State, ctx *hcl.EvalContext) error {
+	for _, zoneRules := range z.Zones {
+		squares, err := parseSquares(zoneRules.Squares)
+		if err != nil {
+			return fmt.Errorf("zone %q: %w", zoneRules.Name, err)
+		}
+		playerSquares := make(map[color.Color][]board.Square, len(zoneRules.PlayerSquares))
+		for name, attr := range zoneRules.PlayerSquares {
+			playerColor, err := color.ColorString(name)
+			if err != nil {
+				return fmt.Errorf("zone %q: %w", zoneRules.Name, err)
+			}
+			var squareStrs []string
+			diags := gohcl.DecodeExpression(attr.Expr, ctx, &squareStrs)
+			if diags.HasErrors() {
+				return diags
+			}
+			playerSquares[playerColor], err = parseSquares(squareStrs)
+			if err != nil {
+				return fmt.Errorf("zone %q: %w", zoneRules.Name, err)
+			}
+		}
+		err = state.AddZone(mess.NewZone(zoneRules.Name, squares, playerSquares))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSquares(squareStrs []string) ([]board.Square, error) {
+	result := make([]board.Square, 0, len(squareStrs))
+	for _, squareStr := range squareStrs {
+		square, err := board.NewSquare(squareStr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, square)
+	}
+	return result, nil
+}
+
+func decodePieceType(
+	controller *controller, pieceTypeRules pieceTypeRules, playerConfigs []mess.PlayerConfig,
+) (*mess.PieceType, error) {
 	pieceType := mess.NewPieceType(pieceTypeRules.Name)
+	for _, playerConfig := range playerConfigs {
+		pieceType.SetPresentation(playerConfig.Color, mess.Presentation{})
+	}
 	for _, motionRules := range pieceTypeRules.Motions {
 		moveGenerator, err := controller.GetCustomFuncAsGenerator(motionRules.GeneratorName)
 		if err != nil {
@@ -85,28 +256,37 @@ func decodePieceType(controller *controller, pieceTypeRules pieceTypeRules) (*me
 			},
 		)
 	}
-	presentation := pieceTypeRules.Presentation
-	if presentation != nil {
-		if presentation.Black != nil {
-			presentation, err := decodePresentation(presentation.Black)
-			if err != nil {
-				return nil, fmt.Errorf("decoding presentation: %w", err)
+	if pieceTypeRules.Presentation != nil {
+		for color, presentationRules := range pieceTypeRules.Presentation.byColor() {
+			if presentationRules == nil {
+				continue
 			}
-			pieceType.SetPresentation(color.Black, presentation)
-		}
-		if presentation.White != nil {
-			presentation, err := decodePresentation(presentation.White)
+			presentation, err := decodePresentation(presentationRules)
 			if err != nil {
-				return nil, fmt.Errorf("decoding presentation: %w", err)
+				return nil, fmt.Errorf("decoding %v presentation: %w", color, err)
 			}
-			pieceType.SetPresentation(color.White, presentation)
+			pieceType.SetPresentation(color, presentation)
 		}
 	}
 	return pieceType, nil
 }
 
+func (p *presentations) byColor() map[color.Color]*presentation {
+	return map[color.Color]*presentation{
+		color.White:  p.White,
+		color.Black:  p.Black,
+		color.Red:    p.Red,
+		color.Green:  p.Green,
+		color.Blue:   p.Blue,
+		color.Yellow: p.Yellow,
+		color.Orange: p.Orange,
+		color.Purple: p.Purple,
+	}
+}
+
 func decodePresentation(presentation *presentation) (mess.Presentation, error) {
 	var symbol rune
+	var code rune
 	var icon mess.AssetKey
 	var rotate bool
 	var err error
@@ -116,13 +296,19 @@ func decodePresentation(presentation *presentation) (mess.Presentation, error) {
 			return mess.Presentation{}, fmt.Errorf("decoding symbol: %w", err)
 		}
 	}
+	if presentation.Code != nil {
+		code, err = decodeSymbol(*presentation.Code)
+		if err != nil {
+			return mess.Presentation{}, fmt.Errorf("decoding code: %w", err)
+		}
+	}
 	if presentation.Icon != nil {
 		icon = mess.NewAssetKey(*presentation.Icon)
 	}
 	if presentation.Rotate != nil {
 		rotate = *presentation.Rotate
 	}
-	return mess.Presentation{Symbol: symbol, Icon: icon, Rotate: rotate}, err
+	return mess.Presentation{Symbol: symbol, Code: code, Icon: icon, Rotate: rotate}, err
 }
 
 func decodeSymbol(symbol string) (rune, error) {
@@ -137,13 +323,65 @@ func decodeSymbol(symbol string) (rune, error) {
 	return r, nil
 }
 
+func (p *playersRules) toPlayerConfigs() ([]mess.PlayerConfig, error) {
+	if p == nil {
+		return mess.DefaultPlayerConfigs, nil
+	}
+	if len(p.Players) == 0 {
+		return nil, fmt.Errorf("no players")
+	}
+
+	result := make([]mess.PlayerConfig, 0, len(p.Players))
+	seen := make(map[color.Color]bool, len(p.Players))
+	for _, playerRules := range p.Players {
+		playerColor, err := color.ColorString(playerRules.Color)
+		if err != nil {
+			return nil, fmt.Errorf("parsing player color: %w", err)
+		} else if seen[playerColor] {
+			return nil, fmt.Errorf("player %v defined more than once", playerColor)
+		}
+		seen[playerColor] = true
+
+		direction := playerRules.ForwardDirection
+		if len(direction) != 2 {
+			return nil, fmt.Errorf("forward direction of player %v must have 2 elements, not %d",
+				playerColor, len(direction))
+		}
+
+		result = append(result, mess.PlayerConfig{
+			Color:            playerColor,
+			ForwardDirection: board.Offset{X: direction[0], Y: direction[1]},
+		})
+	}
+	return result, nil
+}
+
 func (c *rules) placePieces(state *mess.State) error {
-	placementRules := map[color.Color]map[string]string{
-		color.White: c.InitialState.WhitePieces,
-		color.Black: c.InitialState.BlackPieces,
+	placementRules := make(map[string]map[string]string, len(c.InitialState.Pieces)+2)
+	for colorString, pieces := range c.InitialState.Pieces {
+		placementRules[colorString] = pieces
+	}
+	if c.InitialState.WhitePieces != nil {
+		placementRules[color.White.String()] = c.InitialState.WhitePieces
+	}
+	if c.InitialState.BlackPieces != nil {
+		placementRules[color.Black.String()] = c.InitialState.BlackPieces
 	}
-	for color, pieces := range placementRules {
-		player := state.Player(color)
+
+	players := make(map[color.Color]*mess.Player)
+	for _, player := range state.Players() {
+		players[player.Color()] = player
+	}
+
+	for colorString, pieces := range placementRules {
+		playerColor, err := color.ColorString(colorString)
+		if err != nil {
+			return fmt.Errorf("parsing player color: %w", err)
+		}
+		player, ok := players[playerColor]
+		if !ok {
+			return fmt.Errorf("placing pieces of %v: no such player", playerColor)
+		}
 
 		for squareString, pieceTypeName := range pieces {
 			square, err := board.NewSquare(squareString)
@@ -0,0 +1,215 @@
+package bolt
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jostrzol/mess/pkg/board/boardtest"
+	"github.com/jostrzol/mess/pkg/color"
+	pkgevent "github.com/jostrzol/mess/pkg/event"
+	"github.com/jostrzol/mess/pkg/event/eventtest"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/event"
+	"github.com/jostrzol/mess/pkg/server/core/game"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/room"
+	"github.com/stretchr/testify/suite"
+	"go.etcd.io/bbolt"
+)
+
+type RepositorySuite struct {
+	suite.Suite
+	path     string
+	db       *bbolt.DB
+	rooms    *RoomRepository
+	games    *GameRepository
+	observer *eventtest.MockObserver
+}
+
+func (s *RepositorySuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "mess.db")
+	s.reopen()
+}
+
+func (s *RepositorySuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+// reopen closes the database (if open) and creates fresh repositories,
+// so that nothing is cached in memory.
+func (s *RepositorySuite) reopen() {
+	if s.db != nil {
+		s.NoError(s.db.Close())
+	}
+	db, err := Open(s.path)
+	s.Require().NoError(err)
+	s.db = db
+
+	s.rooms = NewRoomRepository()
+	s.rooms.db = db
+	s.games = NewGameRepository()
+	s.games.db = db
+	s.games.rooms = s.rooms
+	s.games.events = &event.Broker{Subject: pkgevent.NewSubject()}
+	s.observer = eventtest.NewMockObserver(s.T())
+	s.games.events.Observe(s.observer)
+}
+
+func (s *RepositorySuite) TestRoomNotFound() {
+	// when
+	_, err := s.rooms.Get(id.New[id.Room]())
+
+	// then
+	s.ErrorIs(err, room.ErrNotFound)
+}
+
+func (s *RepositorySuite) TestSaveRoom() {
+	// given
+	r := s.newRoom()
+	player := id.New[id.Session]()
+	_, err := r.AddPlayer(player)
+	s.NoError(err)
+	spectator := id.New[id.Session]()
+	_, err = r.AddSpectator(spectator)
+	s.NoError(err)
+	message, _, err := r.Chat(player, "hello")
+	s.NoError(err)
+
+	// when
+	s.NoError(s.rooms.Save(r))
+	s.reopen()
+
+	// then
+	loaded, err := s.rooms.Get(r.ID())
+	s.NoError(err)
+	s.Equal(r.ID(), loaded.ID())
+	s.Equal([]id.Session{player}, loaded.Players())
+	s.Equal([]id.Session{spectator}, loaded.Spectators())
+	s.Equal(r.Rules(), loaded.Rules())
+	s.False(loaded.IsStarted())
+	chat, err := loaded.ChatHistory(player)
+	s.NoError(err)
+	s.Require().Len(chat, 1)
+	s.Equal(message.Author, chat[0].Author)
+	s.Equal(message.Text, chat[0].Text)
+	s.True(message.Time.Equal(chat[0].Time))
+}
+
+func (s *RepositorySuite) TestSaveGame() {
+	// given
+	r := s.newRoom()
+	white, black := id.New[id.Session](), id.New[id.Session]()
+	_, err := r.AddPlayer(white)
+	s.NoError(err)
+	_, err = r.AddPlayer(black)
+	s.NoError(err)
+	ev, err := r.StartGame(white)
+	s.NoError(err)
+	g, err := game.New(ev.(*event.GameStarted))
+	s.NoError(err)
+
+	// and
+	_, err = g.PlayTurn(white, 0, mess.Route{mess.MoveOption{SquareVec: mess.SquareVec{
+		From: boardtest.NewSquare("E2"),
+		To:   boardtest.NewSquare("E4"),
+	}}})
+	s.NoError(err)
+	_, err = g.RequestTakeback(black, 1)
+	s.NoError(err)
+
+	// when
+	s.NoError(s.rooms.Save(r))
+	s.NoError(s.games.Save(g))
+	s.reopen()
+
+	// then
+	loaded, err := s.games.GetForRoom(r.ID())
+	s.NoError(err)
+	s.Equal(g.ID(), loaded.ID())
+	s.Equal(1, loaded.State().TurnNumber)
+	s.Equal(black, loaded.CurrentPlayer())
+	s.Equal(g.State().Board.String(), loaded.State().Board.String())
+	s.Equal(&game.Takeback{By: black, Turns: 1}, loaded.Takeback())
+	s.Equal(color.White, loaded.StaticData(white).MyColor)
+	s.observer.ObservedMatch(&event.GameRestored{GameID: g.ID()})
+}
+
+func (s *RepositorySuite) TestSaveGameWithClocks() {
+	// given
+	r := s.newRoom()
+	white, black := id.New[id.Session](), id.New[id.Session]()
+	_, err := r.AddPlayer(white)
+	s.NoError(err)
+	_, err = r.AddPlayer(black)
+	s.NoError(err)
+	timeControl := clock.TimeControl{Kind: clock.Fischer, Base: time.Minute, Increment: time.Second}
+	_, err = r.SetTimeControl(white, timeControl)
+	s.NoError(err)
+	ev, err := r.StartGame(white)
+	s.NoError(err)
+	g, err := game.New(ev.(*event.GameStarted))
+	s.NoError(err)
+
+	// when
+	s.NoError(s.rooms.Save(r))
+	s.NoError(s.games.Save(g))
+	s.reopen()
+
+	// then
+	loadedRoom, err := s.rooms.Get(r.ID())
+	s.NoError(err)
+	s.Equal(timeControl, loadedRoom.TimeControl())
+
+	// and
+	loaded, err := s.games.Get(g.ID())
+	s.NoError(err)
+	s.Equal(timeControl, loaded.TimeControl())
+	s.Len(loaded.State().Clocks, 2)
+}
+
+func (s *RepositorySuite) TestConcurrentGetLoadsOnce() {
+	// given
+	r := s.newRoom()
+	s.NoError(s.rooms.Save(r))
+	s.reopen()
+
+	// when
+	loaded := make([]*room.Room, 8)
+	var wg sync.WaitGroup
+	for i := range loaded {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := s.rooms.Get(r.ID())
+			s.NoError(err)
+			loaded[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	// then
+	for _, result := range loaded {
+		s.Same(loaded[0], result)
+	}
+}
+
+func (s *RepositorySuite) newRoom() *room.Room {
+	filename := "../../../../rules/chess.hcl"
+	src, err := os.ReadFile(filename)
+	s.Require().NoError(err)
+	r, err := room.Restore(&room.Snapshot{
+		ID:        id.New[id.Room](),
+		RulesFile: &rules.File{Src: src, Filename: filepath.Base(filename)},
+	})
+	s.Require().NoError(err)
+	return r
+}
+
+func TestRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RepositorySuite))
+}
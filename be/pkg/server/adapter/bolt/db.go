@@ -0,0 +1,62 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/golobby/container/v3"
+	"github.com/jostrzol/mess/configs/serverconfig"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	roomsBucket = []byte("rooms")
+	gamesBucket = []byte("games")
+)
+
+func Open(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening database %q: %w", path, err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range [][]byte{roomsBucket, gamesBucket} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return fmt.Errorf("creating bucket %q: %w", bucket, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func init() {
+	container.MustSingletonLazy(container.Global, func(config *serverconfig.Config) *bbolt.DB {
+		db, err := Open(config.DatabasePath)
+		if err != nil {
+			panic(err)
+		}
+		return db
+	})
+}
+
+func get(db *bbolt.DB, bucket []byte, key []byte) (value []byte, err error) {
+	err = db.View(func(tx *bbolt.Tx) error {
+		value = append([]byte(nil), tx.Bucket(bucket).Get(key)...)
+		return nil
+	})
+	if len(value) == 0 {
+		value = nil
+	}
+	return
+}
+
+func put(db *bbolt.DB, bucket []byte, key []byte, value []byte) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, value)
+	})
+}
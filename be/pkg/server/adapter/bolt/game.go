@@ -0,0 +1,175 @@
+package bolt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golobby/container/v3"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/adapter/inmem"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/event"
+	"github.com/jostrzol/mess/pkg/server/core/game"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/room"
+	"go.etcd.io/bbolt"
+)
+
+// GameRepository stores games in a bolt database, keeping the ones already
+// loaded in memory. Games are stored as their rules and the routes chosen in
+// each turn, and are replayed when loaded.
+type GameRepository struct {
+	db     *bbolt.DB       `container:"type"`
+	rooms  room.Repository `container:"type"`
+	events *event.Broker   `container:"type"`
+	cache  *inmem.GameRepository
+	// mutex guards the cache, so that a game is restored only once
+	mutex sync.Mutex
+}
+
+func NewGameRepository() *GameRepository {
+	return &GameRepository{cache: inmem.NewGameRepository()}
+}
+
+func init() {
+	container.MustSingletonLazy(container.Global, func() game.Repository {
+		repo := NewGameRepository()
+		container.MustFill(container.Global, repo)
+		return repo
+	})
+}
+
+type gameDto struct {
+	ID          id.Game
+	RoomID      id.Room
+	Players     map[color.Color]id.Session
+	Computer    id.Session
+	Rules       rulesFileDto
+	Setup       string
+	Routes      [][]optionDto
+	Takeback    *game.Takeback
+	TimeControl clock.TimeControl
+	Clocks      map[color.Color]clock.Clock
+	TurnStart   time.Time
+	TimedOut    id.Session
+	DrawOffer   *game.DrawOffer
+	Ending      *game.Resolution
+}
+
+func (r *GameRepository) Save(game *game.Game) error {
+	snapshot := game.Snapshot()
+	routes := make([][]optionDto, 0, len(snapshot.Routes))
+	for _, route := range snapshot.Routes {
+		routes = append(routes, routeToDto(route))
+	}
+	dto := gameDto{
+		ID:          snapshot.ID,
+		RoomID:      snapshot.RoomID,
+		Players:     snapshot.Players,
+		Computer:    snapshot.Computer,
+		Rules:       rulesFileDto(*snapshot.Rules),
+		Setup:       snapshot.Setup,
+		Routes:      routes,
+		Takeback:    snapshot.Takeback,
+		TimeControl: snapshot.TimeControl,
+		Clocks:      snapshot.Clocks,
+		TurnStart:   snapshot.TurnStart,
+		TimedOut:    snapshot.TimedOut,
+		DrawOffer:   snapshot.DrawOffer,
+		Ending:      snapshot.Ending,
+	}
+	value, err := json.Marshal(dto)
+	if err != nil {
+		return fmt.Errorf("marshalling game: %w", err)
+	}
+	err = put(r.db, gamesBucket, []byte(snapshot.ID.String()), value)
+	if err != nil {
+		return fmt.Errorf("saving game: %w", err)
+	}
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	return r.cache.Save(game)
+}
+
+func (r *GameRepository) Get(gameID id.Game) (*game.Game, error) {
+	result, restored, err := r.load(gameID)
+	if err != nil {
+		return nil, err
+	}
+	// notified without the lock, so that the observers can get the game
+	if restored {
+		r.events.Notify(&event.GameRestored{GameID: gameID})
+	}
+	return result, nil
+}
+
+// load gets the game from the cache or restores it from the database, in
+// which case it returns true.
+func (r *GameRepository) load(gameID id.Game) (*game.Game, bool, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+
+	result, err := r.cache.Get(gameID)
+	if err == nil {
+		return result, false, nil
+	}
+
+	value, err := get(r.db, gamesBucket, []byte(gameID.String()))
+	if err != nil {
+		return nil, false, fmt.Errorf("loading game: %w", err)
+	} else if value == nil {
+		return nil, false, game.ErrNotFound
+	}
+	var dto gameDto
+	err = json.Unmarshal(value, &dto)
+	if err != nil {
+		return nil, false, fmt.Errorf("unmarshalling game: %w", err)
+	}
+	snapshot := &game.Snapshot{
+		ID:          dto.ID,
+		RoomID:      dto.RoomID,
+		Players:     dto.Players,
+		Computer:    dto.Computer,
+		Setup:       dto.Setup,
+		Takeback:    dto.Takeback,
+		TimeControl: dto.TimeControl,
+		Clocks:      dto.Clocks,
+		TurnStart:   dto.TurnStart,
+		TimedOut:    dto.TimedOut,
+		DrawOffer:   dto.DrawOffer,
+		Ending:      dto.Ending,
+	}
+	rulesFile := rules.File(dto.Rules)
+	snapshot.Rules = &rulesFile
+	for i, routeDto := range dto.Routes {
+		route, err := routeFromDto(routeDto)
+		if err != nil {
+			return nil, false, fmt.Errorf("decoding route of turn %d: %w", i, err)
+		}
+		snapshot.Routes = append(snapshot.Routes, route)
+	}
+	result, err = game.Restore(snapshot)
+	if err != nil {
+		return nil, false, fmt.Errorf("restoring game: %w", err)
+	}
+
+	err = r.cache.Save(result)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
+func (r *GameRepository) GetForRoom(roomID id.Room) (*game.Game, error) {
+	room, err := r.rooms.Get(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.Game().IsZero() {
+		return nil, game.ErrNotFound
+	}
+	return r.Get(room.Game())
+}
@@ -0,0 +1,122 @@
+package bolt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/golobby/container/v3"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/adapter/inmem"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/room"
+	"go.etcd.io/bbolt"
+)
+
+// RoomRepository stores rooms in a bolt database, keeping the ones already
+// loaded in memory.
+type RoomRepository struct {
+	db    *bbolt.DB `container:"type"`
+	cache *inmem.RoomRepository
+	// mutex guards the cache, so that a room is restored only once
+	mutex sync.Mutex
+}
+
+func NewRoomRepository() *RoomRepository {
+	return &RoomRepository{cache: inmem.NewRoomRepository()}
+}
+
+func init() {
+	container.MustSingletonLazy(container.Global, func() room.Repository {
+		repo := NewRoomRepository()
+		container.MustFill(container.Global, repo)
+		return repo
+	})
+}
+
+type roomDto struct {
+	ID          id.Room
+	Players     []id.Session
+	Spectators  []id.Session
+	Rules       rulesFileDto
+	Game        id.Game
+	Computer    id.Session
+	TimeControl clock.TimeControl
+	Setup       string
+	Chat        []room.ChatMessage
+}
+
+type rulesFileDto struct {
+	Src      []byte
+	Filename string
+}
+
+func (r *RoomRepository) Save(room *room.Room) error {
+	snapshot := room.Snapshot()
+	dto := roomDto{
+		ID:          snapshot.ID,
+		Players:     snapshot.Players,
+		Spectators:  snapshot.Spectators,
+		Rules:       rulesFileDto(*snapshot.RulesFile),
+		Game:        snapshot.Game,
+		Computer:    snapshot.Computer,
+		TimeControl: snapshot.TimeControl,
+		Setup:       snapshot.Setup,
+		Chat:        snapshot.Chat,
+	}
+	value, err := json.Marshal(dto)
+	if err != nil {
+		return fmt.Errorf("marshalling room: %w", err)
+	}
+	err = put(r.db, roomsBucket, []byte(snapshot.ID.String()), value)
+	if err != nil {
+		return fmt.Errorf("saving room: %w", err)
+	}
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	return r.cache.Save(room)
+}
+
+func (r *RoomRepository) Get(roomID id.Room) (*room.Room, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+
+	result, err := r.cache.Get(roomID)
+	if err == nil {
+		return result, nil
+	}
+
+	value, err := get(r.db, roomsBucket, []byte(roomID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("loading room: %w", err)
+	} else if value == nil {
+		return nil, room.ErrNotFound
+	}
+	var dto roomDto
+	err = json.Unmarshal(value, &dto)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling room: %w", err)
+	}
+	rulesFile := rules.File(dto.Rules)
+	result, err = room.Restore(&room.Snapshot{
+		ID:          dto.ID,
+		Players:     dto.Players,
+		Spectators:  dto.Spectators,
+		RulesFile:   &rulesFile,
+		Game:        dto.Game,
+		Computer:    dto.Computer,
+		TimeControl: dto.TimeControl,
+		Setup:       dto.Setup,
+		Chat:        dto.Chat,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("restoring room: %w", err)
+	}
+
+	err = r.cache.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
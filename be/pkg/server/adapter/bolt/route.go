@@ -0,0 +1,72 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/mess"
+)
+
+type optionDto struct {
+	Type      string
+	PieceType string `json:",omitempty"`
+	Square    string `json:",omitempty"`
+	From      string `json:",omitempty"`
+	To        string `json:",omitempty"`
+}
+
+func routeToDto(route mess.Route) []optionDto {
+	result := make([]optionDto, 0, len(route))
+	for _, option := range route {
+		var dto optionDto
+		switch o := option.(type) {
+		case mess.PieceTypeOption:
+			dto = optionDto{Type: "PieceType", PieceType: o.PieceType.Name()}
+		case mess.SquareOption:
+			dto = optionDto{Type: "Square", Square: o.Square.String()}
+		case mess.MoveOption:
+			dto = optionDto{Type: "Move", From: o.SquareVec.From.String(), To: o.SquareVec.To.String()}
+		case mess.UnitOption:
+			dto = optionDto{Type: "Unit"}
+		default:
+			panic(fmt.Errorf("unknown option type %T", option))
+		}
+		result = append(result, dto)
+	}
+	return result
+}
+
+// routeFromDto decodes a route. Piece types are only identified by their names,
+// so they need to be bound to the actual piece types of the game afterwards.
+func routeFromDto(dtos []optionDto) (mess.Route, error) {
+	result := make(mess.Route, 0, len(dtos))
+	for _, dto := range dtos {
+		var option mess.Option
+		switch dto.Type {
+		case "PieceType":
+			option = mess.PieceTypeOption{PieceType: mess.NewPieceType(dto.PieceType)}
+		case "Square":
+			square, err := board.NewSquare(dto.Square)
+			if err != nil {
+				return nil, err
+			}
+			option = mess.SquareOption{Square: square}
+		case "Move":
+			from, err := board.NewSquare(dto.From)
+			if err != nil {
+				return nil, err
+			}
+			to, err := board.NewSquare(dto.To)
+			if err != nil {
+				return nil, err
+			}
+			option = mess.MoveOption{SquareVec: mess.SquareVec{From: from, To: to}}
+		case "Unit":
+			option = mess.UnitOption{}
+		default:
+			return nil, fmt.Errorf("unknown option type %q", dto.Type)
+		}
+		result = append(result, option)
+	}
+	return result, nil
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -17,6 +18,7 @@ import (
 )
 
 const GameURL = "/rooms/:id/game"
+const GameFileContent = "text/plain; charset=utf-8"
 
 type GameHandler struct {
 	service *game.Service        `container:"type"`
@@ -51,7 +53,18 @@ func GetGameState(h *GameHandler, g *gin.Engine) {
 		}
 
 		session := GetSessionData(sessions.Default(c))
-		state, err := h.service.GetGameState(roomID)
+		var state *game.State
+		if turnStr, ok := c.GetQuery("turn"); ok {
+			var turn int
+			turn, err = strconv.Atoi(turnStr)
+			if err != nil {
+				AbortWithError(c, err)
+				return
+			}
+			state, err = h.service.GetGameStateAt(roomID, turn)
+		} else {
+			state, err = h.service.GetGameState(roomID)
+		}
 		if err != nil {
 			AbortWithError(c, err)
 			return
@@ -61,6 +74,24 @@ func GetGameState(h *GameHandler, g *gin.Engine) {
 	})
 }
 
+func GetTurns(h *GameHandler, g *gin.Engine) {
+	g.GET(GameURL+"/turns", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		turns, err := h.service.GetTurns(roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.TurnsFromDomain(turns))
+	})
+}
+
 func GetTurnOptions(h *GameHandler, g *gin.Engine) {
 	g.GET(GameURL+"/options", func(c *gin.Context) {
 		roomID, err := parseUUID[id.Room](c.Param("id"))
@@ -143,6 +174,222 @@ func GetResolution(h *GameHandler, g *gin.Engine) {
 	})
 }
 
+func GetTakeback(h *GameHandler, g *gin.Engine) {
+	g.GET(GameURL+"/takeback", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		takeback, err := h.service.GetTakeback(roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		c.JSON(http.StatusOK, schema.TakebackFromDomain(session.ID, takeback))
+	})
+}
+
+func RequestTakeback(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/takeback", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		var request schema.TakebackRequest
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		takeback, err := h.service.RequestTakeback(session.ID, roomID, request.Turns)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.TakebackFromDomain(session.ID, takeback))
+	})
+}
+
+func AnswerTakeback(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/takeback/answer", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		var answer schema.TakebackAnswer
+		err = c.ShouldBindJSON(&answer)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		state, err := h.service.AnswerTakeback(session.ID, roomID, answer.Accept)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.StateFromDomain(session.ID, state))
+	})
+}
+
+func Resign(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/resignation", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		resolution, err := h.service.Resign(session.ID, roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.ResolutionFromDomain(session.ID, resolution))
+	})
+}
+
+func AbortGame(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/abort", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		resolution, err := h.service.Abort(session.ID, roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.ResolutionFromDomain(session.ID, resolution))
+	})
+}
+
+func GetDrawOffer(h *GameHandler, g *gin.Engine) {
+	g.GET(GameURL+"/draw", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		drawOffer, err := h.service.GetDrawOffer(roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		c.JSON(http.StatusOK, schema.DrawOfferFromDomain(session.ID, drawOffer))
+	})
+}
+
+func OfferDraw(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/draw", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		drawOffer, err := h.service.OfferDraw(session.ID, roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.DrawOfferFromDomain(session.ID, drawOffer))
+	})
+}
+
+func AnswerDrawOffer(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/draw/answer", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		var answer schema.DrawOfferAnswer
+		err = c.ShouldBindJSON(&answer)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		resolution, err := h.service.AnswerDrawOffer(session.ID, roomID, answer.Accept)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.ResolutionFromDomain(session.ID, resolution))
+	})
+}
+
+func ExportGame(h *GameHandler, g *gin.Engine) {
+	g.GET(GameURL+"/export", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		file, err := h.service.ExportGame(roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.Data(http.StatusOK, GameFileContent, []byte(file.String()))
+	})
+}
+
+func ImportGame(h *GameHandler, g *gin.Engine) {
+	g.PUT(GameURL+"/import", func(c *gin.Context) {
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		session := GetSessionData(sessions.Default(c))
+		state, err := h.service.ImportGame(session.ID, roomID, data)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.StateFromDomain(session.ID, state))
+	})
+}
+
 func GetAsset(h *GameHandler, g *gin.Engine) {
 	g.GET(GameURL+"/assets/*key", func(c *gin.Context) {
 		roomID, err := parseUUID[id.Room](c.Param("id"))
@@ -171,8 +418,19 @@ func init() {
 		GetGameStaticData,
 		GetGameState,
 		GetTurnOptions,
+		GetTurns,
 		PlayTurn,
 		GetResolution,
+		GetTakeback,
+		RequestTakeback,
+		AnswerTakeback,
+		Resign,
+		AbortGame,
+		GetDrawOffer,
+		OfferDraw,
+		AnswerDrawOffer,
+		ExportGame,
+		ImportGame,
 		GetAsset,
 	)
 }
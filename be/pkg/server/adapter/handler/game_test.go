@@ -1,9 +1,13 @@
 package handler_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jostrzol/mess/pkg/server/adapter/handler/handlertest"
@@ -76,6 +80,554 @@ func (s *GameSuite) TestGetResolution() {
 	s.NotZero(resolution)
 }
 
+func (s *GameSuite) TestRequestTakeback() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+
+	// and
+	s.Client().chooseTurnOpionRoute(room.ID, 0, firstMoveRoute)
+
+	// when
+	takeback := s.Client().requestTakeback(room.ID, 1)
+
+	// then
+	s.True(takeback.IsPending)
+	s.True(takeback.IsMine)
+
+	// and
+	takeback = opponent.getTakeback(room.ID)
+	s.True(takeback.IsPending)
+	s.False(takeback.IsMine)
+	s.Equal(1, takeback.Turns)
+}
+
+func (s *GameSuite) TestAcceptTakeback() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+
+	// and
+	s.Client().chooseTurnOpionRoute(room.ID, 0, firstMoveRoute)
+	s.Client().requestTakeback(room.ID, 1)
+
+	// when
+	state := opponent.answerTakeback(room.ID, true)
+
+	// then
+	s.Equal(0, state.TurnNumber)
+	s.False(state.IsMyTurn)
+
+	// and
+	takeback := s.Client().getTakeback(room.ID)
+	s.False(takeback.IsPending)
+}
+
+func (s *GameSuite) TestAcceptTakebackMorePlayers() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "halma_4.hcl", readRules("./rules/halma_4.hcl"))
+	opponents := make([]*GameClient, 3)
+	for i := range opponents {
+		opponents[i] = handlertest.CloneWithEmptyJar(s.Client())
+		opponents[i].joinRoom(room.ID)
+	}
+	s.Client().startGame(room.ID)
+	s.Client().chooseTurnOpionRoute(room.ID, 0, halmaFirstMoveRoute)
+	s.Client().requestTakeback(room.ID, 1)
+
+	// when
+	for _, opponent := range opponents[:2] {
+		state := opponent.answerTakeback(room.ID, true)
+		s.Equal(1, state.TurnNumber)
+	}
+
+	// then
+	s.True(opponents[0].getTakeback(room.ID).IsAcceptedByMe)
+	s.False(opponents[2].getTakeback(room.ID).IsAcceptedByMe)
+
+	// when
+	state := opponents[2].answerTakeback(room.ID, true)
+
+	// then
+	s.Equal(0, state.TurnNumber)
+	s.False(s.Client().getTakeback(room.ID).IsPending)
+}
+
+func (s *GameSuite) TestDeclineTakebackMorePlayers() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "halma_4.hcl", readRules("./rules/halma_4.hcl"))
+	opponents := make([]*GameClient, 3)
+	for i := range opponents {
+		opponents[i] = handlertest.CloneWithEmptyJar(s.Client())
+		opponents[i].joinRoom(room.ID)
+	}
+	s.Client().startGame(room.ID)
+	s.Client().chooseTurnOpionRoute(room.ID, 0, halmaFirstMoveRoute)
+	s.Client().requestTakeback(room.ID, 1)
+	opponents[0].answerTakeback(room.ID, true)
+
+	// when
+	state := opponents[1].answerTakeback(room.ID, false)
+
+	// then
+	s.Equal(1, state.TurnNumber)
+	s.False(s.Client().getTakeback(room.ID).IsPending)
+}
+
+func (s *GameSuite) TestDeclineTakeback() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+
+	// and
+	s.Client().chooseTurnOpionRoute(room.ID, 0, firstMoveRoute)
+	s.Client().requestTakeback(room.ID, 1)
+
+	// when
+	state := opponent.answerTakeback(room.ID, false)
+
+	// then
+	s.Equal(1, state.TurnNumber)
+	s.True(state.IsMyTurn)
+}
+
+func (s *GameSuite) TestExportGame() {
+	// given
+	room := s.Client().createStartedRoom()
+
+	// and
+	s.Client().chooseTurnOpionRoute(room.ID, 0, firstMoveRoute)
+
+	// when
+	gameFile := s.Client().exportGame(room.ID)
+
+	// then
+	s.Contains(gameFile, `[Rules "chess.hcl"]`)
+	s.Contains(gameFile, "1. A2-A3")
+}
+
+func (s *GameSuite) TestImportGame() {
+	// given
+	room := s.Client().createStartedRoom()
+
+	// when
+	state := s.Client().importGame(room.ID, "1. A2-A3 2. A7-A6")
+
+	// then
+	s.Equal(2, state.TurnNumber)
+
+	// and
+	gameFile := s.Client().exportGame(room.ID)
+	s.Contains(gameFile, "1. A2-A3\n2. A7-A6\n")
+}
+
+func (s *GameSuite) TestGetTurns() {
+	// given
+	room := s.Client().createStartedRoom()
+	s.Client().importGame(room.ID, "1. A2-A3 2. A7-A6")
+
+	// when
+	turns := s.Client().getTurns(room.ID)
+
+	// then
+	a2, a3 := schema.Square{0, 1}, schema.Square{0, 2}
+	a7, a6 := schema.Square{0, 6}, schema.Square{0, 5}
+	s.Equal([]schema.Turn{
+		{Number: 0, Color: "white", Route: "A2-A3", Events: []schema.TurnEvent{
+			{Type: "PieceMoved", PieceType: "pawn", Color: "white", From: &a2, To: &a3},
+		}},
+		{Number: 1, Color: "black", Route: "A7-A6", Events: []schema.TurnEvent{
+			{Type: "PieceMoved", PieceType: "pawn", Color: "black", From: &a7, To: &a6},
+		}},
+	}, turns)
+}
+
+func (s *GameSuite) TestGetTurnsWithCapture() {
+	// given
+	room := s.Client().createStartedRoom()
+	s.Client().importGame(room.ID, "1. E2-E4 2. D7-D5 3. E4-D5")
+
+	// when
+	turns := s.Client().getTurns(room.ID)
+
+	// then
+	s.Require().Len(turns, 3)
+	e4, d5 := schema.Square{4, 3}, schema.Square{3, 4}
+	s.ElementsMatch([]schema.TurnEvent{
+		{Type: "PieceRemoved", PieceType: "pawn", Color: "black", Square: &d5},
+		{Type: "PieceCaptured", PieceType: "pawn", Color: "black", CapturedBy: "white"},
+		{Type: "PieceMoved", PieceType: "pawn", Color: "white", From: &e4, To: &d5},
+	}, turns[2].Events)
+}
+
+func (s *GameSuite) TestGetTurnsWithDrop() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "dobutsu_shogi.hcl", readRules("./rules/dobutsu_shogi.hcl"))
+	s.NewClient().joinRoom(room.ID)
+	s.Client().startGame(room.ID)
+	s.Client().importGame(room.ID, "1. */B2-B3 2. */A4-A3 3. */chick/C2")
+
+	// when
+	turns := s.Client().getTurns(room.ID)
+
+	// then
+	s.Require().Len(turns, 3)
+	b3, c2 := schema.Square{1, 2}, schema.Square{2, 1}
+	s.Contains(turns[0].Events, schema.TurnEvent{Type: "PieceRemoved", PieceType: "chick", Color: "black", Square: &b3})
+	s.Equal([]schema.TurnEvent{
+		{Type: "PiecePlaced", PieceType: "chick", Color: "white", Square: &c2},
+	}, turns[2].Events)
+}
+
+func (s *GameSuite) TestGetGameStateAtTurn() {
+	// given
+	room := s.Client().createStartedRoom()
+	s.Client().importGame(room.ID, "1. A2-A3 2. A7-A6")
+
+	// when
+	state := s.Client().getGameStateAt(room.ID, 1)
+
+	// then
+	s.Equal(1, state.TurnNumber)
+	s.Equal("black", state.CurrentColor)
+	s.Equal("rnbqkbnr/pppppppp/8/8/8/P7/1PPPPPPP/RNBQKBNR black - 1", state.Setup)
+
+	// and
+	state = s.Client().getGameState(room.ID)
+	s.Equal(2, state.TurnNumber)
+}
+
+func (s *GameSuite) TestGetGameStateAtUnplayedTurn() {
+	// given
+	room := s.Client().createStartedRoom()
+
+	// when
+	res := s.Client().ServeJSON("GET", roomURL(room.ID)+"/game/state?turn=1", nil)
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func (s *GameSuite) TestStartGameFromSetup() {
+	// given
+	room := s.Client().createFilledRoom()
+	s.Client().setSetup(room.ID, "4k3/8/8/8/8/8/4P3/4K3 black - 10")
+	s.Client().startGame(room.ID)
+
+	// when
+	state := s.Client().getGameState(room.ID)
+
+	// then
+	s.Equal(10, state.TurnNumber)
+	s.Equal("black", state.CurrentColor)
+	s.Len(state.Pieces, 3)
+	s.Equal("4k3/8/8/8/8/8/4P3/4K3 black - 10", state.Setup)
+
+	// and
+	gameFile := s.Client().exportGame(room.ID)
+	s.Contains(gameFile, `[Setup "4k3/8/8/8/8/8/4P3/4K3 black - 10"]`)
+}
+
+func (s *GameSuite) TestComputerPlaysTurn() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "dobutsu_shogi.hcl", readRules("./rules/dobutsu_shogi.hcl"))
+	s.Client().addComputer(room.ID)
+	s.Client().startGame(room.ID)
+
+	// when
+	state := s.Client().chooseTurnOpionRoute(room.ID, 0, []any{
+		map[string]any{"Type": "Unit"},
+		map[string]any{
+			"Type": "Move",
+			"From": []any{1, 1},
+			"To":   []any{1, 2},
+		},
+	})
+
+	// then
+	s.Equal(1, state.TurnNumber)
+	state = s.Client().awaitTurn(room.ID, 2)
+	s.True(state.IsMyTurn)
+}
+
+func (s *GameSuite) TestComputerAcceptsTakeback() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "dobutsu_shogi.hcl", readRules("./rules/dobutsu_shogi.hcl"))
+	s.Client().addComputer(room.ID)
+	s.Client().startGame(room.ID)
+	s.Client().chooseTurnOpionRoute(room.ID, 0, []any{
+		map[string]any{"Type": "Unit"},
+		map[string]any{
+			"Type": "Move",
+			"From": []any{1, 1},
+			"To":   []any{1, 2},
+		},
+	})
+	s.Client().awaitTurn(room.ID, 2)
+
+	// when
+	takeback := s.Client().requestTakeback(room.ID, 2)
+
+	// then
+	s.False(takeback.IsPending)
+	state := s.Client().getGameState(room.ID)
+	s.Equal(0, state.TurnNumber)
+	s.True(state.IsMyTurn)
+}
+
+func (s *GameSuite) TestGameStateHasClocks() {
+	// given
+	room, _ := s.Client().createRoomWithOpponent()
+	s.Client().setTimeControl(room.ID, schema.TimeControl{Kind: "sudden_death", BaseMs: 60000})
+	s.Client().startGame(room.ID)
+
+	// when
+	state := s.Client().getGameState(room.ID)
+
+	// then
+	s.Len(state.Clocks, 2)
+	for _, clock := range state.Clocks {
+		s.LessOrEqual(clock.RemainingMs, int64(60000))
+		s.Greater(clock.RemainingMs, int64(0))
+	}
+}
+
+func (s *GameSuite) TestTimeout() {
+	// given
+	room, opponent := s.Client().createRoomWithOpponent()
+	s.Client().setTimeControl(room.ID, schema.TimeControl{Kind: "sudden_death", BaseMs: 100})
+	s.Client().startGame(room.ID)
+
+	// when
+	time.Sleep(200 * time.Millisecond)
+
+	// then
+	s.Equal("Defeat", s.Client().getResolution(room.ID).Status)
+	s.Equal("Win", opponent.getResolution(room.ID).Status)
+
+	// and
+	res := s.Client().ServeJSON("PUT", roomURL(room.ID)+"/game/turns/0", firstMoveRoute)
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func (s *GameSuite) TestNoTimeoutAfterStalemate() {
+	// given
+	room, opponent := s.Client().createRoomWithOpponent()
+	s.Client().setSetup(room.ID, "k7/8/1Q6/8/8/8/8/7K black - 0")
+	s.Client().setTimeControl(room.ID, schema.TimeControl{Kind: "sudden_death", BaseMs: 100})
+	s.Client().startGame(room.ID)
+	s.Equal("stalemate", s.Client().getResolution(room.ID).Reason)
+
+	// when
+	time.Sleep(200 * time.Millisecond)
+
+	// then
+	resolution := s.Client().getResolution(room.ID)
+	s.Equal("Draw", resolution.Status)
+	s.Equal("stalemate", resolution.Reason)
+	s.Equal("Draw", opponent.getResolution(room.ID).Status)
+
+	// and
+	for _, clock := range s.Client().getGameState(room.ID).Clocks {
+		s.Greater(clock.RemainingMs, int64(0))
+	}
+}
+
+func (s *GameSuite) TestSpectatorWatchesGame() {
+	// given
+	room := s.Client().createStartedRoom()
+	spectator := s.NewClient()
+	spectator.spectateRoom(room.ID)
+
+	// when
+	staticData := spectator.getStaticData(room.ID)
+	state := spectator.getGameState(room.ID)
+	spectator.getTurnOptions(room.ID)
+	resolution := spectator.getResolution(room.ID)
+
+	// then
+	s.True(staticData.IsSpectator)
+	s.Empty(staticData.MyColor)
+	s.False(state.IsMyTurn)
+	s.Equal("Unresolved", resolution.Status)
+}
+
+func (s *GameSuite) TestSpectatorCannotPlayTurn() {
+	// given
+	room := s.Client().createStartedRoom()
+	spectator := s.NewClient()
+	spectator.spectateRoom(room.ID)
+
+	// when
+	res := spectator.ServeJSON("PUT", roomURL(room.ID)+"/game/turns/0", firstMoveRoute)
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Contains(res.Body.String(), "you are not a player in this game")
+	s.Equal(0, s.Client().getGameState(room.ID).TurnNumber)
+}
+
+func (s *GameSuite) TestResign() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+
+	// when
+	resolution := s.Client().resign(room.ID)
+
+	// then
+	s.Equal("Defeat", resolution.Status)
+	s.Equal("resignation", resolution.Reason)
+	s.Equal("Win", opponent.getResolution(room.ID).Status)
+
+	// and
+	res := s.Client().ServeJSON("PUT", roomURL(room.ID)+"/game/turns/0", firstMoveRoute)
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Contains(res.Body.String(), "the game has already ended")
+}
+
+func (s *GameSuite) TestTurnAfterRepetition() {
+	// given
+	room := s.Client().createStartedRoom()
+	s.Client().importGame(room.ID, "1. G1-F3 2. G8-F6 3. F3-G1 4. F6-G8 5. G1-F3 6. G8-F6 7. F3-G1 8. F6-G8")
+	s.Equal("repetition", s.Client().getResolution(room.ID).Reason)
+
+	// when
+	res := s.Client().ServeJSON("PUT", roomURL(room.ID)+"/game/turns/8", firstMoveRoute)
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Contains(res.Body.String(), "the game has already ended")
+	s.Equal(8, s.Client().getGameState(room.ID).TurnNumber)
+	s.Equal("repetition", s.Client().getResolution(room.ID).Reason)
+}
+
+func (s *GameSuite) TestAcceptDrawOffer() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+
+	// when
+	drawOffer := s.Client().offerDraw(room.ID)
+
+	// then
+	s.True(drawOffer.IsPending)
+	s.True(drawOffer.IsMine)
+	s.False(opponent.getDrawOffer(room.ID).IsMine)
+
+	// when
+	resolution := opponent.answerDrawOffer(room.ID, true)
+
+	// then
+	s.Equal("Draw", resolution.Status)
+	s.Equal("agreement", resolution.Reason)
+	s.Equal("Draw", s.Client().getResolution(room.ID).Status)
+}
+
+func (s *GameSuite) TestAcceptDrawOfferMorePlayers() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "halma_4.hcl", readRules("./rules/halma_4.hcl"))
+	opponents := make([]*GameClient, 3)
+	for i := range opponents {
+		opponents[i] = handlertest.CloneWithEmptyJar(s.Client())
+		opponents[i].joinRoom(room.ID)
+	}
+	s.Client().startGame(room.ID)
+	s.Client().offerDraw(room.ID)
+
+	// when
+	for _, opponent := range opponents[:2] {
+		resolution := opponent.answerDrawOffer(room.ID, true)
+		s.Equal("Unresolved", resolution.Status)
+	}
+
+	// then
+	s.True(opponents[0].getDrawOffer(room.ID).IsAcceptedByMe)
+	s.False(opponents[2].getDrawOffer(room.ID).IsAcceptedByMe)
+
+	// when
+	resolution := opponents[2].answerDrawOffer(room.ID, true)
+
+	// then
+	s.Equal("Draw", resolution.Status)
+}
+
+func (s *GameSuite) TestDeclineDrawOfferMorePlayers() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "halma_4.hcl", readRules("./rules/halma_4.hcl"))
+	opponents := make([]*GameClient, 3)
+	for i := range opponents {
+		opponents[i] = handlertest.CloneWithEmptyJar(s.Client())
+		opponents[i].joinRoom(room.ID)
+	}
+	s.Client().startGame(room.ID)
+	s.Client().offerDraw(room.ID)
+	opponents[0].answerDrawOffer(room.ID, true)
+
+	// when
+	resolution := opponents[1].answerDrawOffer(room.ID, false)
+
+	// then
+	s.Equal("Unresolved", resolution.Status)
+	s.False(s.Client().getDrawOffer(room.ID).IsPending)
+}
+
+func (s *GameSuite) TestDeclineDrawOffer() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+	s.Client().offerDraw(room.ID)
+
+	// when
+	resolution := opponent.answerDrawOffer(room.ID, false)
+
+	// then
+	s.Equal("Unresolved", resolution.Status)
+	s.False(s.Client().getDrawOffer(room.ID).IsPending)
+}
+
+func (s *GameSuite) TestComputerDeclinesDrawOffer() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "dobutsu_shogi.hcl", readRules("./rules/dobutsu_shogi.hcl"))
+	s.Client().addComputer(room.ID)
+	s.Client().startGame(room.ID)
+
+	// when
+	drawOffer := s.Client().offerDraw(room.ID)
+
+	// then
+	s.False(drawOffer.IsPending)
+	s.Equal("Unresolved", s.Client().getResolution(room.ID).Status)
+}
+
+func (s *GameSuite) TestAbort() {
+	// given
+	room, opponent := s.Client().createStartedRoomWithOpponent()
+
+	// when
+	resolution := opponent.abortGame(room.ID)
+
+	// then
+	s.Equal("Aborted", resolution.Status)
+	s.Equal("aborted", resolution.Reason)
+	s.Empty(resolution.WinnerColor)
+}
+
+func (s *GameSuite) TestAbortAfterFirstTurn() {
+	// given
+	room := s.Client().createStartedRoom()
+	s.Client().chooseTurnOpionRoute(room.ID, 0, firstMoveRoute)
+
+	// when
+	res := s.Client().ServeJSON("PUT", roomURL(room.ID)+"/game/abort", nil)
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Equal("Unresolved", s.Client().getResolution(room.ID).Status)
+}
+
 func (s *GameSuite) TestGetAsset() {
 	// given
 	room := s.Client().createStartedRoom()
@@ -96,6 +648,32 @@ func (c *GameClient) getGameState(roomID uuid.UUID) (state schema.State) {
 	return
 }
 
+// awaitTimeout bounds waiting for asynchronous results in the tests. It's
+// generous, since the race detector slows the evaluation of rules down a lot.
+const awaitTimeout = 30 * time.Second
+
+// awaitTurn polls the game state until the game reaches the given turn, e.g.
+// after the computer plays its turn in the background.
+func (c *GameClient) awaitTurn(roomID uuid.UUID, turn int) (state schema.State) {
+	c.T().Helper()
+	deadline := time.Now().Add(awaitTimeout)
+	for state = c.getGameState(roomID); state.TurnNumber < turn; state = c.getGameState(roomID) {
+		c.Require().True(time.Now().Before(deadline), "turn %d not reached", turn)
+		time.Sleep(10 * time.Millisecond)
+	}
+	return
+}
+
+func (c *GameClient) getGameStateAt(roomID uuid.UUID, turn int) (state schema.State) {
+	c.ServeJSONOkAs("GET", fmt.Sprintf("%s/game/state?turn=%d", roomURL(roomID), turn), nil, &state)
+	return
+}
+
+func (c *GameClient) getTurns(roomID uuid.UUID) (turns []schema.Turn) {
+	c.ServeJSONOkAs("GET", roomURL(roomID)+"/game/turns", nil, &turns)
+	return
+}
+
 type OptionNode struct {
 	Type    string
 	Message string
@@ -111,6 +689,35 @@ func (c *GameClient) createStartedRoom() (room schema.Room) {
 	return
 }
 
+func (c *GameClient) createStartedRoomWithOpponent() (room schema.Room, opponent *GameClient) {
+	room, opponent = c.createRoomWithOpponent()
+	room = c.startGame(room.ID)
+	return
+}
+
+func (c *GameClient) createRoomWithOpponent() (room schema.Room, opponent *GameClient) {
+	room = c.createRoom()
+	opponent = handlertest.CloneWithEmptyJar(c)
+	room = opponent.joinRoom(room.ID)
+	return
+}
+
+var firstMoveRoute = []any{
+	map[string]any{
+		"Type": "Move",
+		"From": []any{0, 1},
+		"To":   []any{0, 2},
+	},
+}
+
+var halmaFirstMoveRoute = []any{
+	map[string]any{
+		"Type": "Move",
+		"From": []any{3, 0},
+		"To":   []any{4, 0},
+	},
+}
+
 func (c *GameClient) getTurnOptions(roomID uuid.UUID) (optionTree *OptionNode) {
 	c.ServeJSONOkAs("GET", roomURL(roomID)+"/game/options", nil, &optionTree)
 	return
@@ -126,6 +733,62 @@ func (c *GameClient) getResolution(roomID uuid.UUID) (resolution schema.Resoluti
 	return
 }
 
+func (c *GameClient) getTakeback(roomID uuid.UUID) (takeback schema.Takeback) {
+	c.ServeJSONOkAs("GET", roomURL(roomID)+"/game/takeback", nil, &takeback)
+	return
+}
+
+func (c *GameClient) requestTakeback(roomID uuid.UUID, turns int) (takeback schema.Takeback) {
+	request := schema.TakebackRequest{Turns: turns}
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game/takeback", request, &takeback)
+	return
+}
+
+func (c *GameClient) answerTakeback(roomID uuid.UUID, accept bool) (state schema.State) {
+	answer := schema.TakebackAnswer{Accept: accept}
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game/takeback/answer", answer, &state)
+	return
+}
+
+func (c *GameClient) resign(roomID uuid.UUID) (resolution schema.Resolution) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game/resignation", nil, &resolution)
+	return
+}
+
+func (c *GameClient) abortGame(roomID uuid.UUID) (resolution schema.Resolution) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game/abort", nil, &resolution)
+	return
+}
+
+func (c *GameClient) getDrawOffer(roomID uuid.UUID) (drawOffer schema.DrawOffer) {
+	c.ServeJSONOkAs("GET", roomURL(roomID)+"/game/draw", nil, &drawOffer)
+	return
+}
+
+func (c *GameClient) offerDraw(roomID uuid.UUID) (drawOffer schema.DrawOffer) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game/draw", nil, &drawOffer)
+	return
+}
+
+func (c *GameClient) answerDrawOffer(roomID uuid.UUID, accept bool) (resolution schema.Resolution) {
+	answer := schema.DrawOfferAnswer{Accept: accept}
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game/draw/answer", answer, &resolution)
+	return
+}
+
+func (c *GameClient) exportGame(roomID uuid.UUID) string {
+	res := c.ServeOk("GET", roomURL(roomID)+"/game/export", nil)
+	bytes, err := io.ReadAll(res.Body)
+	c.NoError(err)
+	return string(bytes)
+}
+
+func (c *GameClient) importGame(roomID uuid.UUID, gameFile string) (state schema.State) {
+	res := c.ServeOk("PUT", roomURL(roomID)+"/game/import", []byte(gameFile))
+	c.NoError(json.NewDecoder(res.Body).Decode(&state))
+	return
+}
+
 func (c *GameClient) getAsset(roomID uuid.UUID, assetKey string) []byte {
 	res := c.ServeJSONOk("GET", roomURL(roomID)+"/game/assets"+assetKey, nil)
 	bytes, err := io.ReadAll(res.Body)
@@ -133,6 +796,14 @@ func (c *GameClient) getAsset(roomID uuid.UUID, assetKey string) []byte {
 	return bytes
 }
 
+func readRules(filename string) string {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+	return string(src)
+}
+
 func TestGameSuite(t *testing.T) {
 	suite.Run(t, new(GameSuite))
 }
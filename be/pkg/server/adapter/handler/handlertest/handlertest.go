@@ -12,9 +12,12 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/jostrzol/mess/configs/serverconfig"
 	"github.com/jostrzol/mess/pkg/logger"
 	"github.com/jostrzol/mess/pkg/server/ioc"
@@ -39,6 +42,10 @@ func setupRouter() *gin.Engine {
 		SessionSecret:  "secret",
 		Port:           54321,
 		IncomingOrigin: "http://localhost:4000",
+		ComputerDepth:  1,
+		// keep the heartbeat out of the way of the websocket tests
+		HeartbeatPeriod:    time.Minute,
+		MaxWebsocketErrors: 3,
 	}
 	ioc.MustSingleton(config)
 	logger, err := logger.New(config.IsProduction)
@@ -201,4 +208,22 @@ func (c *httpClient) logRequest(method string, url string, reqBody []byte, res *
 	c.T().Logf("================================================================================")
 }
 
+// DialWebsocket connects to the websocket with the client's session. The
+// connection is closed at the end of the test.
+func (c *httpClient) DialWebsocket() *websocket.Conn {
+	c.T().Helper()
+	server := httptest.NewServer(c.g)
+	c.T().Cleanup(server.Close)
+
+	header := http.Header{}
+	for _, cookie := range c.jar.Cookies(&root) {
+		header.Add("Cookie", cookie.String())
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/websocket"
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	c.Require().NoError(err)
+	c.T().Cleanup(func() { conn.Close() })
+	return conn
+}
+
 var root = url.URL{Scheme: "http", Path: "/"}
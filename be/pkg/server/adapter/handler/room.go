@@ -73,6 +73,49 @@ func JoinRoom(h *RoomHandler, g *gin.Engine) {
 	})
 }
 
+func SpectateRoom(h *RoomHandler, g *gin.Engine) {
+	g.PUT("/rooms/:id/spectators", func(c *gin.Context) {
+		session := GetSessionData(sessions.Default(c))
+
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		r, err := h.service.SpectateRoom(session.ID, roomID)
+		switch {
+		case errors.Is(err, room.ErrAlreadySpectating):
+			break
+		case err != nil:
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.RoomFromDomain(r))
+	})
+}
+
+func AddComputer(h *RoomHandler, g *gin.Engine) {
+	g.PUT("/rooms/:id/computer", func(c *gin.Context) {
+		session := GetSessionData(sessions.Default(c))
+
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		r, err := h.service.AddComputer(session.ID, roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.RoomFromDomain(r))
+	})
+}
+
 func GetRules(h *RoomHandler, g *gin.Engine) {
 	g.GET("/rooms/:id/rules", func(c *gin.Context) {
 		roomID, err := parseUUID[id.Room](c.Param("id"))
@@ -118,6 +161,113 @@ func SetRules(h *RoomHandler, g *gin.Engine) {
 	})
 }
 
+func SetTimeControl(h *RoomHandler, g *gin.Engine) {
+	g.PUT("/rooms/:id/timecontrol", func(c *gin.Context) {
+		session := GetSessionData(sessions.Default(c))
+
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		var request schema.TimeControl
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		timeControl, err := request.ToDomain()
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		r, err := h.service.SetTimeControl(session.ID, roomID, timeControl)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.RoomFromDomain(r))
+	})
+}
+
+func SetSetup(h *RoomHandler, g *gin.Engine) {
+	g.PUT("/rooms/:id/setup", func(c *gin.Context) {
+		session := GetSessionData(sessions.Default(c))
+
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		var request schema.RoomSetup
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		r, err := h.service.SetSetup(session.ID, roomID, request.Setup)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.RoomFromDomain(r))
+	})
+}
+
+func GetChat(h *RoomHandler, g *gin.Engine) {
+	g.GET("/rooms/:id/chat", func(c *gin.Context) {
+		session := GetSessionData(sessions.Default(c))
+
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		r, messages, err := h.service.GetChat(session.ID, roomID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.ChatFromDomain(session.ID, r, messages))
+	})
+}
+
+func SendChatMessage(h *RoomHandler, g *gin.Engine) {
+	g.POST("/rooms/:id/chat", func(c *gin.Context) {
+		session := GetSessionData(sessions.Default(c))
+
+		roomID, err := parseUUID[id.Room](c.Param("id"))
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		var request schema.ChatMessageRequest
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		r, message, err := h.service.Chat(session.ID, roomID, request.Text)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.ChatMessageFromDomain(session.ID, r, message))
+	})
+}
+
 func StartGame(h *RoomHandler, g *gin.Engine) {
 	g.PUT("/rooms/:id/game", func(c *gin.Context) {
 		session := GetSessionData(sessions.Default(c))
@@ -156,8 +306,14 @@ func init() {
 		CreateRoom,
 		GetRoom,
 		JoinRoom,
+		SpectateRoom,
+		AddComputer,
 		GetRules,
 		SetRules,
+		SetTimeControl,
+		SetSetup,
+		GetChat,
+		SendChatMessage,
 		StartGame,
 		HandleWebsocket,
 	)
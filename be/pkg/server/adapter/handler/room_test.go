@@ -1,6 +1,8 @@
 package handler_test
 
 import (
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -43,6 +45,40 @@ func (s *RoomSuite) TestJoinRoomDifferentClient() {
 	s.Equal(2, room.Players)
 }
 
+func (s *RoomSuite) TestSpectateRoom() {
+	// given
+	room := s.Client().createFilledRoom()
+
+	// when
+	for i := 0; i < 3; i++ {
+		room = s.NewClient().spectateRoom(room.ID)
+	}
+
+	// then
+	s.Equal(2, room.Players)
+	s.Equal(3, room.Spectators)
+	s.True(room.IsStartable)
+}
+
+func (s *RoomSuite) TestSpectatorCannotModifyRoom() {
+	// given
+	room := s.Client().createRoom()
+	spectator := s.NewClient()
+	spectator.spectateRoom(room.ID)
+
+	// when
+	res := spectator.Serve("PUT", roomURL(room.ID)+"/rules/rules.hcl", []byte("board { width = 2; height = 2 }"))
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Contains(res.Body.String(), "spectators cannot modify the room")
+
+	// and
+	res = spectator.ServeJSON("PUT", roomURL(room.ID)+"/players", nil)
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Equal(1, s.Client().getRoom(room.ID).Players)
+}
+
 func (s *RoomSuite) TestGetRoom() {
 	// given
 	room := s.Client().createRoom()
@@ -75,6 +111,246 @@ func (s *RoomSuite) TestSetRules() {
 	s.Equal(room.RulesFilename, "rules.hcl")
 }
 
+func (s *RoomSuite) TestSetRulesMorePlayers() {
+	// given
+	room := s.Client().createRoom()
+
+	// when
+	s.Client().setRules(room.ID, "halma_4.hcl", readRules("./rules/halma_4.hcl"))
+
+	// then
+	room = s.Client().getRoom(room.ID)
+	s.Equal(4, room.PlayersNeeded)
+
+	// and
+	for i := 0; i < 2; i++ {
+		room = s.NewClient().joinRoom(room.ID)
+	}
+	s.Equal(3, room.Players)
+	s.False(room.IsStartable)
+
+	// when
+	room = s.NewClient().joinRoom(room.ID)
+
+	// then
+	s.True(room.IsStartable)
+	room = s.Client().startGame(room.ID)
+	s.True(room.IsStarted)
+}
+
+func (s *RoomSuite) TestSetRulesFewerPlayers() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setRules(room.ID, "halma_4.hcl", readRules("./rules/halma_4.hcl"))
+	for i := 0; i < 2; i++ {
+		s.NewClient().joinRoom(room.ID)
+	}
+
+	// when
+	res := s.Client().Serve("PUT", roomURL(room.ID)+"/rules/chess.hcl", []byte(readRules("./rules/chess.hcl")))
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Contains(res.Body.String(), "too many players for the current rules")
+
+	// and
+	room = s.Client().getRoom(room.ID)
+	s.Equal("halma_4.hcl", room.RulesFilename)
+	s.Equal(4, room.PlayersNeeded)
+}
+
+func (s *RoomSuite) TestSetRulesAfterStart() {
+	// given
+	room := s.Client().createFilledRoom()
+	s.Client().startGame(room.ID)
+
+	// when
+	res := s.Client().Serve("PUT", roomURL(room.ID)+"/rules/rules.hcl", []byte("board { width = 2; height = 2 }"))
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Contains(res.Body.String(), "game is already started")
+}
+
+func (s *RoomSuite) TestSetTimeControl() {
+	// given
+	room := s.Client().createRoom()
+
+	// when
+	room = s.Client().setTimeControl(room.ID, schema.TimeControl{
+		Kind:        "fischer",
+		BaseMs:      60000,
+		IncrementMs: 2000,
+	})
+
+	// then
+	s.Equal("fischer", room.TimeControl.Kind)
+	s.EqualValues(60000, room.TimeControl.BaseMs)
+	s.EqualValues(2000, room.TimeControl.IncrementMs)
+
+	// and
+	room = s.Client().getRoom(room.ID)
+	s.Equal("fischer", room.TimeControl.Kind)
+}
+
+func (s *RoomSuite) TestSetTimeControlInvalid() {
+	// given
+	room := s.Client().createRoom()
+
+	// when
+	res := s.Client().ServeJSON("PUT", roomURL(room.ID)+"/timecontrol", schema.TimeControl{
+		Kind:   "sudden_death",
+		BaseMs: -1,
+	})
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+
+	// and
+	room = s.Client().getRoom(room.ID)
+	s.Equal("none", room.TimeControl.Kind)
+}
+
+func (s *RoomSuite) TestSetSetup() {
+	// given
+	room := s.Client().createRoom()
+	setup := "4k3/8/8/8/8/8/4P3/4K3 black - 10"
+
+	// when
+	room = s.Client().setSetup(room.ID, setup)
+
+	// then
+	s.Equal(setup, room.Setup)
+
+	// and
+	room = s.Client().getRoom(room.ID)
+	s.Equal(setup, room.Setup)
+}
+
+func (s *RoomSuite) TestSetSetupInvalid() {
+	// given
+	room := s.Client().createRoom()
+
+	// when
+	res := s.Client().ServeJSON("PUT", roomURL(room.ID)+"/setup", schema.RoomSetup{
+		Setup: "4k3/8/8 black - 10",
+	})
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+
+	// and
+	room = s.Client().getRoom(room.ID)
+	s.Empty(room.Setup)
+}
+
+func (s *RoomSuite) TestSetRulesClearsSetup() {
+	// given
+	room := s.Client().createRoom()
+	s.Client().setSetup(room.ID, "4k3/8/8/8/8/8/4P3/4K3 black - 10")
+
+	// when
+	s.Client().setRules(room.ID, "chess.hcl", s.Client().getRules(room.ID))
+
+	// then
+	room = s.Client().getRoom(room.ID)
+	s.Empty(room.Setup)
+}
+
+func (s *RoomSuite) TestChat() {
+	// given
+	room := s.Client().createRoom()
+	opponent := handlertest.CloneWithEmptyJar(s.Client())
+	opponent.joinRoom(room.ID)
+
+	// when
+	message := s.Client().sendChatMessage(room.ID, " good luck ")
+	opponent.sendChatMessage(room.ID, "have fun")
+
+	// then
+	s.Equal(0, message.Seat)
+	s.True(message.IsMine)
+	s.Equal("good luck", message.Text)
+
+	// and
+	chat := s.Client().getChat(room.ID)
+	s.Require().Len(chat, 2)
+	s.Equal("good luck", chat[0].Text)
+	s.True(chat[0].IsMine)
+	s.Equal("have fun", chat[1].Text)
+	s.Equal(1, chat[1].Seat)
+	s.False(chat[1].IsMine)
+}
+
+func (s *RoomSuite) TestChatSpectator() {
+	// given
+	room := s.Client().createRoom()
+	spectator := handlertest.CloneWithEmptyJar(s.Client())
+	spectator.spectateRoom(room.ID)
+
+	// when
+	message := spectator.sendChatMessage(room.ID, "hi")
+
+	// then
+	s.Equal(-1, message.Seat)
+}
+
+func (s *RoomSuite) TestChatNotInRoom() {
+	// given
+	room := s.Client().createRoom()
+	outsider := handlertest.CloneWithEmptyJar(s.Client())
+
+	// when
+	res := outsider.ServeJSON("GET", roomURL(room.ID)+"/chat", nil)
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func (s *RoomSuite) TestChatMessageInvalid() {
+	// given
+	room := s.Client().createRoom()
+
+	for _, text := range []string{"   ", strings.Repeat("a", 501)} {
+		// when
+		res := s.Client().ServeJSON("POST", roomURL(room.ID)+"/chat", schema.ChatMessageRequest{Text: text})
+
+		// then
+		s.Equal(http.StatusBadRequest, res.Code)
+	}
+
+	// and
+	s.Empty(s.Client().getChat(room.ID))
+}
+
+func (s *RoomSuite) TestChatRateLimit() {
+	// given
+	room := s.Client().createRoom()
+	for i := 0; i < 5; i++ {
+		s.Client().sendChatMessage(room.ID, "spam")
+	}
+
+	// when
+	res := s.Client().ServeJSON("POST", roomURL(room.ID)+"/chat", schema.ChatMessageRequest{Text: "spam"})
+
+	// then
+	s.Equal(http.StatusBadRequest, res.Code)
+	s.Len(s.Client().getChat(room.ID), 5)
+}
+
+func (s *RoomSuite) TestAddComputer() {
+	// given
+	room := s.Client().createRoom()
+
+	// when
+	room = s.Client().addComputer(room.ID)
+
+	// then
+	s.Equal(2, room.Players)
+	s.True(room.HasComputer)
+	s.True(room.IsStartable)
+}
+
 func (s *RoomSuite) TestStartGame() {
 	// given
 	room := s.Client().createFilledRoom()
@@ -98,6 +374,16 @@ func (c *RoomClient) joinRoom(roomID uuid.UUID) (room schema.Room) {
 	return
 }
 
+func (c *RoomClient) spectateRoom(roomID uuid.UUID) (room schema.Room) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/spectators", nil, &room)
+	return
+}
+
+func (c *RoomClient) addComputer(roomID uuid.UUID) (room schema.Room) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/computer", nil, &room)
+	return
+}
+
 func (c *RoomClient) getRoom(roomID uuid.UUID) (room schema.Room) {
 	c.ServeJSONOkAs("GET", roomURL(roomID), nil, &room)
 	return
@@ -121,6 +407,26 @@ func (c *RoomClient) setRules(roomID uuid.UUID, filename string, data string) {
 	c.ServeOk("PUT", roomURL(roomID)+"/rules/"+filename, []byte(data))
 }
 
+func (c *RoomClient) setTimeControl(roomID uuid.UUID, timeControl schema.TimeControl) (room schema.Room) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/timecontrol", timeControl, &room)
+	return
+}
+
+func (c *RoomClient) setSetup(roomID uuid.UUID, setup string) (room schema.Room) {
+	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/setup", schema.RoomSetup{Setup: setup}, &room)
+	return
+}
+
+func (c *RoomClient) getChat(roomID uuid.UUID) (chat []schema.ChatMessage) {
+	c.ServeJSONOkAs("GET", roomURL(roomID)+"/chat", nil, &chat)
+	return
+}
+
+func (c *RoomClient) sendChatMessage(roomID uuid.UUID, text string) (message schema.ChatMessage) {
+	c.ServeJSONOkAs("POST", roomURL(roomID)+"/chat", schema.ChatMessageRequest{Text: text}, &message)
+	return
+}
+
 func (c *RoomClient) startGame(roomID uuid.UUID) (room schema.Room) {
 	c.ServeJSONOkAs("PUT", roomURL(roomID)+"/game", nil, &room)
 	return
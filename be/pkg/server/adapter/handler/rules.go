@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/adapter/schema"
 	"github.com/jostrzol/mess/pkg/server/ioc"
 )
 
@@ -26,6 +28,21 @@ func FormatRules(_ *RulesHandler, g *gin.Engine) {
 	})
 }
 
+// ValidateRules responds with the problems found in the rules file.
+func ValidateRules(_ *RulesHandler, g *gin.Engine) {
+	g.PUT("/rules/validate", func(c *gin.Context) {
+		src, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		filename := c.DefaultQuery("filename", "rules.hcl")
+		diags := rules.Lint(&rules.File{Src: src, Filename: filename})
+		c.JSON(http.StatusOK, schema.DiagnosticsFromDomain(diags))
+	})
+}
+
 func init() {
 	ioc.MustHandlerFill(FormatRules)
+	ioc.MustHandlerFill(ValidateRules)
 }
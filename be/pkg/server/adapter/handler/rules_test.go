@@ -1,10 +1,13 @@
 package handler_test
 
 import (
+	"encoding/json"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/jostrzol/mess/pkg/server/adapter/handler/handlertest"
+	"github.com/jostrzol/mess/pkg/server/adapter/schema"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -23,6 +26,31 @@ func (s *RulesSuite) TestFormatRules() {
 	s.Equal("a { b = 2 }", out)
 }
 
+func (s *RulesSuite) TestValidateRules() {
+	// given
+	src := strings.Replace(readRules("./rules/chess.hcl"), `"motion_hook"`, `"motion_hok"`, 1)
+
+	// when
+	diags := s.Client().validateRules(src)
+
+	// then
+	s.Require().Len(diags, 1)
+	s.Equal("error", diags[0].Severity)
+	s.Equal("Undefined function", diags[0].Summary)
+	s.NotNil(diags[0].Range)
+}
+
+func (s *RulesSuite) TestValidateRulesValid() {
+	// given
+	src := readRules("./rules/chess.hcl")
+
+	// when
+	diags := s.Client().validateRules(src)
+
+	// then
+	s.Empty(diags)
+}
+
 type RulesClient struct{ *handlertest.BaseClient }
 
 func (c *RulesClient) formatRules(src string) (out string) {
@@ -32,6 +60,12 @@ func (c *RulesClient) formatRules(src string) (out string) {
 	return string(bytes)
 }
 
+func (c *RulesClient) validateRules(src string) (diags []schema.Diagnostic) {
+	res := c.ServeOk("PUT", "/rules/validate", []byte(src))
+	c.NoError(json.NewDecoder(res.Body).Decode(&diags))
+	return
+}
+
 func TestRulesSuite(t *testing.T) {
 	suite.Run(t, new(RulesSuite))
 }
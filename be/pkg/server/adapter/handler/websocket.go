@@ -25,10 +25,12 @@ var upgrader = websocket.Upgrader{
 const wsTimeout = time.Second
 
 type WsHandler struct {
-	logger     *zap.Logger         `container:"type"`
-	websockets *inmem.WsRepository `container:"type"`
-	rooms      room.Repository     `container:"type"`
-	games      game.Repository     `container:"type"`
+	logger      *zap.Logger         `container:"type"`
+	websockets  *inmem.WsRepository `container:"type"`
+	rooms       room.Repository     `container:"type"`
+	games       game.Repository     `container:"type"`
+	roomService *room.Service       `container:"type"`
+	gameService *game.Service       `container:"type"`
 }
 
 func init() {
@@ -51,14 +53,22 @@ func (h *WsHandler) handle(c *gin.Context) error {
 		}
 	}()
 
-	channel := h.websockets.New(session.ID)
+	ws := h.websockets.New(session.ID)
 	conn.SetCloseHandler(func(code int, text string) error {
 		h.logger.Info("peer closed session channel, cleaning up", zap.Stringer("session", session.ID))
-		h.websockets.Close(session.ID)
+		h.websockets.CloseChannel(session.ID, ws)
 		return nil
 	})
+	go h.readCommands(conn, session.ID, ws)
+
+	for {
+		var event schema.Event
+		select {
+		case event = <-ws.Events():
+		case <-ws.Done():
+			return nil
+		}
 
-	for event, ok := <-channel; ok; event, ok = <-channel {
 		bytes, err := schema.MarshalEvent(event)
 		if err != nil {
 			h.logger.Error("marshaling websocket message", zap.Error(err))
@@ -78,67 +88,194 @@ func (h *WsHandler) handle(c *gin.Context) error {
 			continue
 		}
 	}
-	return nil
+}
+
+// eventFor builds the event sent to the given member of the room.
+type eventFor func(session id.Session) schema.Event
+
+func same(ev schema.Event) eventFor {
+	return func(id.Session) schema.Event { return ev }
 }
 
 func (h *WsHandler) Handle(evnt event.Event) {
 	var err error
-	var eventToSend schema.Event
+	var events []eventFor
 	var players []id.Session
 	var author id.Session
 	switch ev := evnt.(type) {
 	case *event.PlayerJoined:
-		players, err = h.playersInRoom(ev.RoomID)
+		players, events, err = h.roomEvent(ev.RoomID, func(r *room.Room) schema.Event {
+			return schema.PlayerJoinedFromDomain(r, ev.PlayerID)
+		})
 		author = ev.PlayerID
-		eventToSend = &schema.RoomChanged{}
+	case *event.SpectatorJoined:
+		players, events, err = h.roomEvent(ev.RoomID, roomChanged)
+		author = ev.SpectatorID
 	case *event.RoomRulesChanged:
-		players, err = h.playersInRoom(ev.RoomID)
+		players, events, err = h.roomEvent(ev.RoomID, func(r *room.Room) schema.Event {
+			return schema.RulesChangedFromDomain(r)
+		})
+		author = ev.By
+	case *event.RoomTimeControlChanged:
+		players, events, err = h.roomEvent(ev.RoomID, roomChanged)
 		author = ev.By
-		eventToSend = &schema.RoomChanged{}
-	case *event.GameStarted:
-		players, err = h.playersInRoom(ev.RoomID)
+	case *event.RoomSetupChanged:
+		players, events, err = h.roomEvent(ev.RoomID, roomChanged)
+		author = ev.By
+	case *event.ChatMessageSent:
+		players, events, err = h.chatMessage(ev)
+		author = ev.By
+	case *event.GameCreated:
+		players, events, err = h.gameStarted(ev.GameID)
 		author = ev.By
-		eventToSend = &schema.RoomChanged{}
 	case *event.GameChanged:
-		players, err = h.playersInGame(ev.GameID)
+		players, events, err = h.gameChanged(ev.GameID, ev.Turn)
+		author = ev.By
+	case *event.GameTimedOut:
+		players, events, err = h.gameChanged(ev.GameID, nil)
+	case *event.TakebackRequested:
+		players, err = h.membersOfGame(ev.GameID)
+		author = ev.By
+		events = []eventFor{same(&schema.TakebackRequested{Turns: ev.Turns})}
+	case *event.TakebackAccepted:
+		players, err = h.membersOfGame(ev.GameID)
+		author = ev.By
+		events = []eventFor{same(&schema.TakebackAccepted{})}
+	case *event.TakebackDeclined:
+		players, err = h.membersOfGame(ev.GameID)
+		author = ev.By
+		events = []eventFor{same(&schema.TakebackDeclined{})}
+	case *event.DrawOffered:
+		players, err = h.membersOfGame(ev.GameID)
 		author = ev.By
-		eventToSend = &schema.GameChanged{}
+		events = []eventFor{same(&schema.DrawOffered{})}
+	case *event.DrawAccepted:
+		players, err = h.membersOfGame(ev.GameID)
+		author = ev.By
+		events = []eventFor{same(&schema.DrawAccepted{})}
+	case *event.DrawDeclined:
+		players, err = h.membersOfGame(ev.GameID)
+		author = ev.By
+		events = []eventFor{same(&schema.DrawDeclined{})}
 	}
 	if err != nil {
 		h.logger.Error("sending event", zap.Any("event", evnt), zap.Error(err))
 		return
 	}
-	h.sendToOpponents(players, author, eventToSend)
+	for _, build := range events {
+		h.send(players, author, build)
+	}
 }
 
-func (h *WsHandler) sendToOpponents(players []id.Session, author id.Session, event schema.Event) {
+// send sends the event to the players. The author gets only an
+// acknowledgement of it.
+func (h *WsHandler) send(players []id.Session, author id.Session, build eventFor) {
 	for _, player := range players {
-		if player != author {
-			h.logger.Debug("sending websocket message", zap.Any("event", event), zap.Stringer("session", player))
-			err := h.websockets.Send(player, event)
-			if err != nil {
-				h.logger.Error("sending event",
-					zap.Stringer("target", player),
-					zap.String("eventType", event.EventType()),
-					zap.Error(err),
-				)
-			}
+		event := build(player)
+		if player == author {
+			event = &schema.Ack{Event: event}
 		}
+		h.logger.Debug("sending websocket message", zap.Any("event", event), zap.Stringer("session", player))
+		err := h.websockets.Send(player, event)
+		if err != nil {
+			h.logger.Error("sending event",
+				zap.Stringer("target", player),
+				zap.String("eventType", event.EventType()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func roomChanged(r *room.Room) schema.Event {
+	return &schema.RoomChanged{Room: schema.RoomFromDomain(r)}
+}
+
+func (h *WsHandler) roomEvent(roomID id.Room, build func(*room.Room) schema.Event) ([]id.Session, []eventFor, error) {
+	r, err := h.rooms.Get(roomID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return r.Members(), []eventFor{same(build(r))}, nil
 }
 
-func (h *WsHandler) playersInRoom(roomID id.Room) ([]id.Session, error) {
+// membersOfRoom returns both the players and the spectators of the room.
+func (h *WsHandler) membersOfRoom(roomID id.Room) ([]id.Session, error) {
 	room, err := h.rooms.Get(roomID)
 	if err != nil {
 		return nil, err
 	}
-	return room.Players(), nil
+	return room.Members(), nil
 }
 
-func (h *WsHandler) playersInGame(gameID id.Game) ([]id.Session, error) {
+// membersOfGame returns both the players and the spectators of the game.
+func (h *WsHandler) membersOfGame(gameID id.Game) ([]id.Session, error) {
 	game, err := h.games.Get(gameID)
 	if err != nil {
 		return nil, err
 	}
-	return game.Players(), nil
+	return h.membersOfRoom(game.RoomID())
+}
+
+func (h *WsHandler) chatMessage(ev *event.ChatMessageSent) ([]id.Session, []eventFor, error) {
+	r, err := h.rooms.Get(ev.RoomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	message := &room.ChatMessage{Author: ev.By, Text: ev.Text, Time: ev.Time}
+	return r.Members(), []eventFor{func(session id.Session) schema.Event {
+		return schema.ChatMessageFromDomain(session, r, message)
+	}}, nil
+}
+
+func (h *WsHandler) gameStarted(gameID id.Game) ([]id.Session, []eventFor, error) {
+	g, err := h.games.Get(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	members, err := h.membersOfRoom(g.RoomID())
+	if err != nil {
+		return nil, nil, err
+	}
+	return members, []eventFor{func(session id.Session) schema.Event {
+		return &schema.GameStarted{StaticData: schema.StaticDataFromDomain(g.StaticData(session))}
+	}}, nil
+}
+
+// gameChanged builds the events for the members of the game's room. If the game
+// changed because of playing a turn, only the turn is sent, unless it was
+// already taken back. If the game has ended, the resolution follows.
+func (h *WsHandler) gameChanged(gameID id.Game, turnNumber *int) ([]id.Session, []eventFor, error) {
+	g, err := h.games.Get(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	members, err := h.membersOfRoom(g.RoomID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state := g.State()
+	var turn *game.Turn
+	if turnNumber != nil {
+		if played, ok := g.Turn(*turnNumber); ok {
+			turn = &played
+		}
+	}
+	var events []eventFor
+	if turn != nil {
+		events = append(events, same(schema.TurnPlayedFromDomain(*turn, state)))
+	} else {
+		events = append(events, func(session id.Session) schema.Event {
+			return &schema.GameChanged{State: schema.StateFromDomain(session, state)}
+		})
+	}
+
+	resolution := g.Resolution()
+	if resolution.IsResolved {
+		events = append(events, func(session id.Session) schema.Event {
+			return &schema.GameEnded{Resolution: schema.ResolutionFromDomain(session, resolution)}
+		})
+	}
+	return members, events, nil
 }
@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/jostrzol/mess/pkg/server/adapter/handler/handlertest"
+	"github.com/jostrzol/mess/pkg/server/adapter/schema"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/exp/slices"
+)
+
+type WebsocketSuite struct {
+	handlertest.HandlerSuite[WebsocketClient]
+}
+
+func (s *WebsocketSuite) TestPing() {
+	// given
+	s.Client().createRoom()
+	ws := s.Client().dial()
+
+	// when
+	ws.send("ping", "Ping", uuid.UUID{}, nil)
+
+	// then
+	reply := ws.readUntil("Reply")
+	s.Equal(schema.EventsVersion, reply.Version)
+	s.JSONEq(`{"RequestID": "ping"}`, string(reply.Data))
+}
+
+func (s *WebsocketSuite) TestPlayTurn() {
+	// given
+	room := s.Client().createStartedRoom()
+	ws := s.Client().dial()
+
+	// when
+	ws.send("turn", "PlayTurn", room.ID, map[string]any{
+		"Turn":  0,
+		"Route": firstMoveRoute,
+	})
+
+	// then
+	ack := ws.readUntil("TurnPlayed")
+	s.True(ack.IsAck)
+	var turnPlayed schema.TurnPlayed
+	s.NoError(json.Unmarshal(ack.Data, &turnPlayed))
+	s.Equal("A2-A3", turnPlayed.Turn.Route)
+
+	// and
+	reply := ws.readUntil("Reply")
+	var data struct {
+		RequestID string
+		Result    schema.State
+	}
+	s.NoError(json.Unmarshal(reply.Data, &data))
+	s.Equal("turn", data.RequestID)
+	s.Equal(1, data.Result.TurnNumber)
+}
+
+func (s *WebsocketSuite) TestRequestOptions() {
+	// given
+	room := s.Client().createStartedRoom()
+	ws := s.Client().dial()
+
+	// when
+	ws.send("options", "RequestOptions", room.ID, nil)
+
+	// then
+	reply := ws.readUntil("Reply")
+	var data struct {
+		RequestID string
+		Result    OptionNode
+	}
+	s.NoError(json.Unmarshal(reply.Data, &data))
+	s.Equal("options", data.RequestID)
+	s.Equal("Move", data.Result.Type)
+}
+
+func (s *WebsocketSuite) TestCommandsInOrder() {
+	// given
+	room := s.Client().createStartedRoom()
+	ws := s.Client().dial()
+
+	// when
+	ws.send("turn", "PlayTurn", room.ID, map[string]any{
+		"Turn":  0,
+		"Route": firstMoveRoute,
+	})
+	ws.send("options", "RequestOptions", room.ID, nil)
+	ws.send("ping", "Ping", uuid.UUID{}, nil)
+
+	// then
+	var requestIDs []string
+	for len(requestIDs) < 3 {
+		reply := ws.readUntil("Reply")
+		var data struct{ RequestID string }
+		s.NoError(json.Unmarshal(reply.Data, &data))
+		requestIDs = append(requestIDs, data.RequestID)
+	}
+	s.Subset(requestIDs, []string{"turn", "options", "ping"})
+	s.Less(slices.Index(requestIDs, "turn"), slices.Index(requestIDs, "options"))
+}
+
+func (s *WebsocketSuite) TestUnknownCommand() {
+	// given
+	s.Client().createRoom()
+	ws := s.Client().dial()
+
+	// when
+	ws.send("unknown", "Unknown", uuid.UUID{}, nil)
+
+	// then
+	reply := ws.readUntil("Error")
+	var data schema.ErrorReply
+	s.NoError(json.Unmarshal(reply.Data, &data))
+	s.Equal("unknown", data.RequestID)
+	s.Equal(http.StatusBadRequest, data.Error.Status)
+}
+
+func (s *WebsocketSuite) TestInvalidTurn() {
+	// given
+	room := s.Client().createStartedRoom()
+	ws := s.Client().dial()
+
+	// when
+	ws.send("turn", "PlayTurn", room.ID, map[string]any{
+		"Turn":  1,
+		"Route": firstMoveRoute,
+	})
+
+	// then
+	reply := ws.readUntil("Error")
+	var data schema.ErrorReply
+	s.NoError(json.Unmarshal(reply.Data, &data))
+	s.Equal("turn", data.RequestID)
+	s.Equal(http.StatusBadRequest, data.Error.Status)
+}
+
+func (s *WebsocketSuite) TestChat() {
+	// given
+	room, opponent := s.Client().createRoomWithOpponent()
+	ws := s.Client().dial()
+	opponentWs := (&WebsocketClient{*opponent}).dial()
+
+	// when
+	ws.send("chat", "Chat", room.ID, map[string]any{"Text": "  good luck  "})
+
+	// then
+	message := opponentWs.readUntil("ChatMessage")
+	s.False(message.IsAck)
+	var chatMessage schema.ChatMessage
+	s.NoError(json.Unmarshal(message.Data, &chatMessage))
+	s.Equal(0, chatMessage.Seat)
+	s.Equal("good luck", chatMessage.Text)
+
+	// and
+	ack := ws.readUntil("ChatMessage")
+	s.True(ack.IsAck)
+}
+
+type WebsocketClient struct{ GameClient }
+
+type wsConn struct {
+	*suite.Suite
+	conn *websocket.Conn
+}
+
+type wsEvent struct {
+	EventType string
+	Version   int
+	IsAck     bool
+	Data      json.RawMessage
+}
+
+// dial connects to the websocket and waits until the connection is ready to
+// receive events.
+func (c *WebsocketClient) dial() *wsConn {
+	ws := &wsConn{Suite: c.Suite, conn: c.DialWebsocket()}
+	ws.send("dial", "Ping", uuid.UUID{}, nil)
+	ws.readUntil("Reply")
+	return ws
+}
+
+func (ws *wsConn) send(requestID string, commandType string, roomID uuid.UUID, data any) {
+	ws.T().Helper()
+	dataBytes, err := json.Marshal(data)
+	ws.Require().NoError(err)
+	err = ws.conn.WriteJSON(schema.Command{
+		RequestID:   requestID,
+		CommandType: commandType,
+		RoomID:      roomID,
+		Data:        dataBytes,
+	})
+	ws.Require().NoError(err)
+}
+
+// readUntil reads the events until the one of the given type, skipping the
+// others.
+func (ws *wsConn) readUntil(eventType string) (event wsEvent) {
+	ws.T().Helper()
+	for event.EventType != eventType {
+		err := ws.conn.SetReadDeadline(time.Now().Add(awaitTimeout))
+		ws.Require().NoError(err)
+		event = wsEvent{}
+		err = ws.conn.ReadJSON(&event)
+		ws.Require().NoError(err)
+	}
+	return
+}
+
+func TestWebsocketSuite(t *testing.T) {
+	suite.Run(t, new(WebsocketSuite))
+}
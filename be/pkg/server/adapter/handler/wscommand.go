@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+	"github.com/jostrzol/mess/pkg/server/adapter/inmem"
+	"github.com/jostrzol/mess/pkg/server/adapter/schema"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/usrerr"
+	"go.uber.org/zap"
+)
+
+// maxQueuedCommands bounds the commands of a connection waiting to be executed.
+// Once the queue is full, reading from the connection waits for it.
+const maxQueuedCommands = 16
+
+// readCommands reads the commands sent by the client until the connection is
+// closed. The commands are executed one by one in the order they arrive, except
+// for pings, which are answered straight away. The replies are sent through the
+// session's channel, so that only one goroutine writes to the connection.
+func (h *WsHandler) readCommands(conn *websocket.Conn, session id.Session, ws *inmem.Websocket) {
+	commands := make(chan *schema.Command, maxQueuedCommands)
+	defer close(commands)
+	go h.executeCommands(session, commands)
+
+	for {
+		_, bytes, err := conn.ReadMessage()
+		if err != nil {
+			h.logger.Info("reading websocket message", zap.Stringer("session", session), zap.Error(err))
+			h.websockets.CloseChannel(session, ws)
+			return
+		}
+
+		var command schema.Command
+		err = json.Unmarshal(bytes, &command)
+		switch {
+		case err != nil:
+			h.reply(session, &command, nil, usrerr.Errorf("invalid command: %w", err))
+		case command.CommandType == "Ping":
+			h.reply(session, &command, nil, nil)
+		default:
+			commands <- &command
+		}
+	}
+}
+
+// executeCommands executes the commands until the channel is closed.
+func (h *WsHandler) executeCommands(session id.Session, commands <-chan *schema.Command) {
+	for command := range commands {
+		result, err := h.execute(session, command)
+		h.reply(session, command, result, err)
+	}
+}
+
+// reply sends back the result of the command, or the error if it failed.
+func (h *WsHandler) reply(session id.Session, command *schema.Command, result any, err error) {
+	var reply schema.Event = &schema.Reply{RequestID: command.RequestID, Result: result}
+	if err != nil {
+		h.logger.Info("executing websocket command", zap.Stringer("session", session), zap.Error(err))
+		reply = &schema.ErrorReply{RequestID: command.RequestID, Error: schema.NewError(err)}
+	}
+
+	err = h.websockets.Send(session, reply)
+	if err != nil {
+		h.logger.Error("sending reply", zap.Stringer("session", session), zap.Error(err))
+	}
+}
+
+func (h *WsHandler) execute(session id.Session, command *schema.Command) (any, error) {
+	roomID := id.Room{BaseID: id.BaseID{UUID: command.RoomID}}
+	switch command.CommandType {
+	case "RequestOptions":
+		optionTree, err := h.gameService.GetTurnOptions(roomID)
+		if err != nil {
+			return nil, err
+		}
+		return schema.OptionNodeFromDomain(optionTree), nil
+	case "PlayTurn":
+		var data schema.PlayTurnCommand
+		if err := json.Unmarshal(command.Data, &data); err != nil {
+			return nil, usrerr.Errorf("invalid command data: %w", err)
+		}
+		routeDto, err := schema.UnmarshalRoute(data.Route)
+		if err != nil {
+			return nil, usrerr.Errorf("invalid route: %w", err)
+		}
+		state, err := h.gameService.GetGameState(roomID)
+		if err != nil {
+			return nil, err
+		}
+		route, err := routeDto.ToDomain(state)
+		if err != nil {
+			return nil, err
+		}
+		state, err = h.gameService.PlayTurn(session, roomID, data.Turn, route)
+		if err != nil {
+			return nil, err
+		}
+		return schema.StateFromDomain(session, state), nil
+	case "Chat":
+		var data schema.ChatMessageRequest
+		if err := json.Unmarshal(command.Data, &data); err != nil {
+			return nil, usrerr.Errorf("invalid command data: %w", err)
+		}
+		r, message, err := h.roomService.Chat(session, roomID, data.Text)
+		if err != nil {
+			return nil, err
+		}
+		return schema.ChatMessageFromDomain(session, r, message), nil
+	default:
+		return nil, usrerr.Errorf("unknown command %q", command.CommandType)
+	}
+}
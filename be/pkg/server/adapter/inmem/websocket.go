@@ -10,23 +10,43 @@ import (
 	"github.com/jostrzol/mess/pkg/server/adapter/schema"
 	"github.com/jostrzol/mess/pkg/server/core/id"
 	"go.uber.org/zap"
-	"golang.org/x/exp/maps"
 )
 
 type WsRepository struct {
 	logger   *zap.Logger          `container:"type"`
 	config   *serverconfig.Config `container:"type"`
-	channels map[id.Session]*websocket
+	channels map[id.Session]*Websocket
 	mutex    sync.Mutex
 }
 
-type websocket struct {
-	channel    chan (schema.Event)
+// Websocket passes the events to the connection of a session. The events
+// channel is never closed, so that sending can't race with closing; Done is
+// closed instead once the websocket is removed.
+type Websocket struct {
+	events     chan (schema.Event)
+	done       chan struct{}
 	errorCount int
 }
 
+func (ws *Websocket) Events() <-chan (schema.Event) {
+	return ws.events
+}
+
+func (ws *Websocket) Done() <-chan struct{} {
+	return ws.done
+}
+
+func (ws *Websocket) send(event schema.Event) error {
+	select {
+	case ws.events <- event:
+		return nil
+	case <-ws.done:
+		return fmt.Errorf("sending to a closed socket")
+	}
+}
+
 func NewWsRepository() *WsRepository {
-	repo := WsRepository{channels: make(map[id.Session]*websocket)}
+	repo := WsRepository{channels: make(map[id.Session]*Websocket)}
 	container.MustFill(container.Global, &repo)
 	go repo.heartbeatTask()
 	return &repo
@@ -38,24 +58,25 @@ func init() {
 	})
 }
 
-func (r *WsRepository) New(sessionID id.Session) <-chan (schema.Event) {
+func (r *WsRepository) New(sessionID id.Session) *Websocket {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	old, ok := r.channels[sessionID]
 	if ok {
 		r.logger.Warn("closing old websocket channel", zap.Stringer("session", sessionID))
-		close(old.channel)
+		close(old.done)
 	}
-	ws := &websocket{
-		channel:    make(chan (schema.Event)),
+	ws := &Websocket{
+		events:     make(chan (schema.Event)),
+		done:       make(chan struct{}),
 		errorCount: 0,
 	}
 	r.channels[sessionID] = ws
-	return ws.channel
+	return ws
 }
 
 func (r *WsRepository) Send(sessionID id.Session, event schema.Event) error {
-	var ws *websocket
+	var ws *Websocket
 	func() {
 		r.mutex.Lock()
 		defer r.mutex.Unlock()
@@ -64,35 +85,43 @@ func (r *WsRepository) Send(sessionID id.Session, event schema.Event) error {
 	if ws == nil {
 		return fmt.Errorf("sending to a nonexistant socket")
 	}
-	ws.channel <- event
-	return nil
+	return ws.send(event)
 }
 
 func (r *WsRepository) heartbeatTask() {
 	for {
 		time.Sleep(r.config.HeartbeatPeriod)
-		var websockets map[id.Session]*websocket
-		func() {
-			r.mutex.Lock()
-			defer r.mutex.Unlock()
-			websockets = maps.Clone(r.channels)
-		}()
-		for sessionID, ws := range websockets {
-			if ws.errorCount >= r.config.MaxWebsocketErrors {
-				r.logger.Info(
-					"websocket error count exceeded maximum; removing",
-					zap.Stringer("session", sessionID),
-					zap.Int("maximum", r.config.MaxWebsocketErrors))
-				close(ws.channel)
-				delete(r.channels, sessionID)
-				continue
-			}
-			ws.channel <- &schema.Heartbeat{}
+		for _, ws := range r.removeFaulty() {
+			// the socket could have been closed in the meantime
+			_ = ws.send(&schema.Heartbeat{})
 		}
 	}
 }
 
+// removeFaulty closes the websockets which exceeded the maximum error count
+// and returns the remaining ones.
+func (r *WsRepository) removeFaulty() []*Websocket {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	result := make([]*Websocket, 0, len(r.channels))
+	for sessionID, ws := range r.channels {
+		if ws.errorCount >= r.config.MaxWebsocketErrors {
+			r.logger.Info(
+				"websocket error count exceeded maximum; removing",
+				zap.Stringer("session", sessionID),
+				zap.Int("maximum", r.config.MaxWebsocketErrors))
+			close(ws.done)
+			delete(r.channels, sessionID)
+			continue
+		}
+		result = append(result, ws)
+	}
+	return result
+}
+
 func (r *WsRepository) IndicateError(sessionID id.Session) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	ws := r.channels[sessionID]
 	if ws != nil {
 		ws.errorCount++
@@ -104,9 +133,21 @@ func (r *WsRepository) IndicateError(sessionID id.Session) {
 func (r *WsRepository) Close(sessionID id.Session) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	c, ok := r.channels[sessionID]
+	ws, ok := r.channels[sessionID]
 	if ok {
 		delete(r.channels, sessionID)
-		close(c.channel)
+		close(ws.done)
+	}
+}
+
+// CloseChannel closes the websocket of the session, unless it was already
+// replaced by a newer connection of the same session.
+func (r *WsRepository) CloseChannel(sessionID id.Session, ws *Websocket) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	current, ok := r.channels[sessionID]
+	if ok && current == ws {
+		delete(r.channels, sessionID)
+		close(ws.done)
 	}
 }
@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/room"
+	"golang.org/x/exp/slices"
+)
+
+type ChatMessageRequest struct {
+	Text string
+}
+
+// ChatMessage carries the seat of the author, i.e. their index among the
+// players of the room, or -1 if they are a spectator.
+type ChatMessage struct {
+	Seat   int
+	IsMine bool
+	Text   string
+	Time   time.Time
+}
+
+func ChatFromDomain(session id.Session, r *room.Room, messages []room.ChatMessage) []*ChatMessage {
+	result := make([]*ChatMessage, 0, len(messages))
+	for i := range messages {
+		result = append(result, ChatMessageFromDomain(session, r, &messages[i]))
+	}
+	return result
+}
+
+func ChatMessageFromDomain(session id.Session, r *room.Room, m *room.ChatMessage) *ChatMessage {
+	return &ChatMessage{
+		Seat:   slices.Index(r.Players(), m.Author),
+		IsMine: m.Author == session,
+		Text:   m.Text,
+		Time:   m.Time,
+	}
+}
+
+func (e *ChatMessage) EventType() string { return "ChatMessage" }
@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/usrerr"
+)
+
+// TimeControl is expressed in milliseconds. Kind is one of: none,
+// sudden_death, fischer, bronstein or byo_yomi.
+type TimeControl struct {
+	Kind        string
+	BaseMs      int64
+	IncrementMs int64
+	DelayMs     int64
+	PeriodMs    int64
+	Periods     int
+}
+
+func TimeControlFromDomain(timeControl clock.TimeControl) TimeControl {
+	return TimeControl{
+		Kind:        timeControl.Kind.String(),
+		BaseMs:      timeControl.Base.Milliseconds(),
+		IncrementMs: timeControl.Increment.Milliseconds(),
+		DelayMs:     timeControl.Delay.Milliseconds(),
+		PeriodMs:    timeControl.Period.Milliseconds(),
+		Periods:     timeControl.Periods,
+	}
+}
+
+func (t TimeControl) ToDomain() (clock.TimeControl, error) {
+	kind, err := clock.KindString(t.Kind)
+	if err != nil {
+		return clock.TimeControl{}, usrerr.Errorf("parsing time control: %w", err)
+	}
+	return clock.TimeControl{
+		Kind:      kind,
+		Base:      time.Duration(t.BaseMs) * time.Millisecond,
+		Increment: time.Duration(t.IncrementMs) * time.Millisecond,
+		Delay:     time.Duration(t.DelayMs) * time.Millisecond,
+		Period:    time.Duration(t.PeriodMs) * time.Millisecond,
+		Periods:   t.Periods,
+	}, nil
+}
+
+type Clock struct {
+	RemainingMs int64
+	Periods     int
+}
+
+func clocksFromDomain(clocks map[color.Color]clock.Clock) map[string]Clock {
+	if len(clocks) == 0 {
+		return nil
+	}
+	result := make(map[string]Clock, len(clocks))
+	for color, c := range clocks {
+		result[color.String()] = Clock{
+			RemainingMs: c.Remaining.Milliseconds(),
+			Periods:     c.Periods,
+		}
+	}
+	return result
+}
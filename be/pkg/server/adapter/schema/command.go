@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Command is a message sent by the client over the websocket. The client
+// chooses the RequestID, which is sent back with the reply to the command.
+// Commands are executed in the order they were sent, except for pings, which
+// are replied to straight away.
+// Data depends on the CommandType:
+//   - "PlayTurn": PlayTurnCommand, replied with the State,
+//   - "RequestOptions": none, replied with the OptionNode,
+//   - "Ping": none, replied with nothing,
+//   - "Chat": ChatMessageRequest, replied with the ChatMessage.
+type Command struct {
+	RequestID   string
+	CommandType string
+	RoomID      uuid.UUID
+	Data        json.RawMessage
+}
+
+type PlayTurnCommand struct {
+	Turn  int
+	Route json.RawMessage
+}
+
+// Reply is sent in response to a successful command.
+type Reply struct {
+	RequestID string
+	Result    any `json:",omitempty"`
+}
+
+func (e *Reply) EventType() string { return "Reply" }
+
+// ErrorReply is sent in response to a failed command.
+type ErrorReply struct {
+	RequestID string
+	Error     *Error
+}
+
+func (e *ErrorReply) EventType() string { return "Error" }
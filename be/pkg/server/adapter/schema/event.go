@@ -3,34 +3,149 @@ package schema
 import (
 	"encoding/json"
 	"reflect"
+
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/server/core/game"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/room"
+	"golang.org/x/exp/slices"
 )
 
+// EventsVersion is the version of the websocket events protocol, sent with
+// every event. It changes whenever the payload of an event changes in an
+// incompatible way.
+const EventsVersion = 1
+
 type Event interface {
 	EventType() string
 }
 
-type RoomChanged struct{}
+// Ack acknowledges the event to its author, who does not get the event itself.
+type Ack struct {
+	Event
+}
+
+// RoomChanged carries the room after a change of its settings or spectators.
+type RoomChanged struct {
+	Room *Room
+}
 
 func (e *RoomChanged) EventType() string { return "RoomChanged" }
 
-type GameStarted struct{}
+// PlayerJoined carries the seat the player took, i.e. their index among the
+// players of the room.
+type PlayerJoined struct {
+	Seat       int
+	IsComputer bool
+	Room       *Room
+}
+
+func PlayerJoinedFromDomain(r *room.Room, player id.Session) *PlayerJoined {
+	return &PlayerJoined{
+		Seat:       slices.Index(r.Players(), player),
+		IsComputer: r.Computer() == player,
+		Room:       RoomFromDomain(r),
+	}
+}
+
+func (e *PlayerJoined) EventType() string { return "PlayerJoined" }
+
+type RulesChanged struct {
+	Filename string
+	Hash     string
+	Room     *Room
+}
+
+func RulesChangedFromDomain(r *room.Room) *RulesChanged {
+	return &RulesChanged{
+		Filename: r.RulesFile.Filename,
+		Hash:     notation.RulesHash(r.RulesFile),
+		Room:     RoomFromDomain(r),
+	}
+}
+
+func (e *RulesChanged) EventType() string { return "RulesChanged" }
+
+type GameStarted struct {
+	StaticData *StaticData
+}
 
 func (e *GameStarted) EventType() string { return "GameStarted" }
 
-type GameChanged struct{}
+// TurnPlayed carries the turn with the changes it made to the board, so that
+// the state can be updated without fetching it again.
+type TurnPlayed struct {
+	Turn   Turn
+	Clocks map[string]Clock `json:",omitempty"`
+}
+
+func TurnPlayedFromDomain(turn game.Turn, s *game.State) *TurnPlayed {
+	return &TurnPlayed{
+		Turn:   TurnFromDomain(turn),
+		Clocks: clocksFromDomain(s.Clocks),
+	}
+}
+
+func (e *TurnPlayed) EventType() string { return "TurnPlayed" }
+
+// GameChanged carries the whole state after a change other than playing a
+// turn, e.g. a takeback.
+type GameChanged struct {
+	State *State
+}
 
 func (e *GameChanged) EventType() string { return "GameChanged" }
 
+type GameEnded struct {
+	Resolution *Resolution
+}
+
+func (e *GameEnded) EventType() string { return "GameEnded" }
+
+type TakebackRequested struct {
+	Turns int
+}
+
+func (e *TakebackRequested) EventType() string { return "TakebackRequested" }
+
+type TakebackAccepted struct{}
+
+func (e *TakebackAccepted) EventType() string { return "TakebackAccepted" }
+
+type TakebackDeclined struct{}
+
+func (e *TakebackDeclined) EventType() string { return "TakebackDeclined" }
+
+type DrawOffered struct{}
+
+func (e *DrawOffered) EventType() string { return "DrawOffered" }
+
+type DrawAccepted struct{}
+
+func (e *DrawAccepted) EventType() string { return "DrawAccepted" }
+
+type DrawDeclined struct{}
+
+func (e *DrawDeclined) EventType() string { return "DrawDeclined" }
+
 type Heartbeat struct{}
 
 func (e *Heartbeat) EventType() string { return "Heartbeat" }
 
 func MarshalEvent(event Event) ([]byte, error) {
+	ack, isAck := event.(*Ack)
+	if isAck {
+		event = ack.Event
+	}
 	obj := struct {
 		Data      Event `json:",omitempty"`
 		EventType string
+		Version   int
+		IsAck     bool `json:",omitempty"`
 	}{
 		EventType: event.EventType(),
+		Version:   EventsVersion,
+		IsAck:     isAck,
 	}
 	val := reflect.Indirect(reflect.ValueOf(event))
 	if val.NumField() != 0 {
@@ -5,14 +5,22 @@ import (
 	"github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
 	"github.com/jostrzol/mess/pkg/server/core/game"
 	"github.com/jostrzol/mess/pkg/server/core/id"
+	"golang.org/x/exp/slices"
 )
 
 type StaticData struct {
-	ID        uuid.UUID
-	BoardSize BoardSize
-	MyColor   string
+	ID              uuid.UUID
+	BoardSize       BoardSize
+	DisabledSquares []Square
+	Topology        string
+	Zones           []Zone
+	MyColor         string
+	IsSpectator     bool
+	Colors          []string
+	TimeControl     TimeControl
 }
 
 type BoardSize struct {
@@ -21,48 +29,244 @@ type BoardSize struct {
 }
 
 func StaticDataFromDomain(s *game.StaticData) *StaticData {
-	return &StaticData{
-		ID:        s.ID.UUID,
-		BoardSize: BoardSize(s.BoardSize),
-		MyColor:   s.MyColor.String(),
+	result := &StaticData{
+		ID:              s.ID.UUID,
+		BoardSize:       BoardSize(s.BoardSize),
+		DisabledSquares: squaresFromDomain(s.DisabledSquares),
+		Topology:        s.Topology.String(),
+		Zones:           zonesFromDomain(s.Zones),
+		IsSpectator:     s.IsSpectator,
+		Colors:          colorsFromDomain(s.Colors),
+		TimeControl:     TimeControlFromDomain(s.TimeControl),
 	}
+	if !s.IsSpectator {
+		result.MyColor = s.MyColor.String()
+	}
+	return result
+}
+
+// Zone squares are the squares common to all the players, while player squares
+// belong to the zone only for the player of the given color.
+type Zone struct {
+	Name          string
+	Squares       []Square
+	PlayerSquares map[string][]Square
 }
 
+func zonesFromDomain(zones []*mess.Zone) []Zone {
+	result := make([]Zone, 0, len(zones))
+	for _, zone := range zones {
+		playerSquares := make(map[string][]Square)
+		for color, squares := range zone.PlayerSquares() {
+			playerSquares[color.String()] = squaresFromDomain(squares)
+		}
+		result = append(result, Zone{
+			Name:          zone.Name(),
+			Squares:       squaresFromDomain(zone.CommonSquares()),
+			PlayerSquares: playerSquares,
+		})
+	}
+	return result
+}
+
+func colorsFromDomain(colors []color.Color) []string {
+	result := make([]string, 0, len(colors))
+	for _, color := range colors {
+		result = append(result, color.String())
+	}
+	return result
+}
+
+// Resolution status is one of: Unresolved, Win, Defeat, Draw, Aborted, or
+// Ended for the spectators. WinnerColor is empty if there is no winner.
+// Reason is one of mess.EndReason values, e.g. checkmate or resignation.
 type Resolution struct {
-	Status string
+	Status      string
+	WinnerColor string `json:",omitempty"`
+	Reason      string
 }
 
 func ResolutionFromDomain(session id.Session, r *game.Resolution) *Resolution {
-	var status string
+	result := &Resolution{Reason: r.Reason.String()}
+	if !r.Winner.IsZero() {
+		result.WinnerColor = r.WinnerColor.String()
+	}
 	switch {
 	case !r.IsResolved:
-		status = "Unresolved"
+		result.Status = "Unresolved"
+	case r.Reason == mess.Aborted:
+		result.Status = "Aborted"
+	case !slices.Contains(r.Players, session):
+		result.Status = "Ended"
+	default:
+		result.Status = playerStatus(session, r)
+	}
+	return result
+}
+
+func playerStatus(session id.Session, r *game.Resolution) string {
+	switch {
 	case r.Winner == session:
-		status = "Win"
+		return "Win"
+	case r.Loser == session:
+		return "Defeat"
 	case r.Winner.IsZero():
-		status = "Draw"
+		return "Draw"
 	default:
-		status = "Defeat"
+		return "Defeat"
+	}
+}
+
+type Takeback struct {
+	IsPending      bool
+	IsMine         bool
+	IsAcceptedByMe bool
+	Turns          int
+}
+
+func TakebackFromDomain(session id.Session, t *game.Takeback) *Takeback {
+	if t == nil {
+		return &Takeback{}
+	}
+	return &Takeback{
+		IsPending:      true,
+		IsMine:         t.By == session,
+		IsAcceptedByMe: slices.Contains(t.AcceptedBy, session),
+		Turns:          t.Turns,
+	}
+}
+
+type DrawOffer struct {
+	IsPending      bool
+	IsMine         bool
+	IsAcceptedByMe bool
+}
+
+func DrawOfferFromDomain(session id.Session, d *game.DrawOffer) *DrawOffer {
+	if d == nil {
+		return &DrawOffer{}
+	}
+	return &DrawOffer{
+		IsPending:      true,
+		IsMine:         d.By == session,
+		IsAcceptedByMe: slices.Contains(d.AcceptedBy, session),
 	}
-	return &Resolution{Status: status}
+}
+
+type DrawOfferAnswer struct {
+	Accept bool
+}
+
+type TakebackRequest struct {
+	Turns int
+}
+
+type TakebackAnswer struct {
+	Accept bool
 }
 
 type State struct {
-	ID         uuid.UUID
-	TurnNumber int
-	Pieces     []Piece
-	IsMyTurn   bool
+	ID           uuid.UUID
+	TurnNumber   int
+	Pieces       []Piece
+	IsMyTurn     bool
+	CurrentColor string
+	Setup        string           `json:",omitempty"`
+	Clocks       map[string]Clock `json:",omitempty"`
 }
 
 func StateFromDomain(session id.Session, s *game.State) *State {
 	return &State{
-		ID:         s.ID.UUID,
-		TurnNumber: s.TurnNumber,
-		Pieces:     piecesFromDomain(s.Board.AllPieces()),
-		IsMyTurn:   s.CurrentPlayer == session,
+		ID:           s.ID.UUID,
+		TurnNumber:   s.TurnNumber,
+		Pieces:       piecesFromDomain(s.Board.AllPieces()),
+		IsMyTurn:     s.CurrentPlayer == session,
+		CurrentColor: s.CurrentColor.String(),
+		Setup:        s.Setup,
+		Clocks:       clocksFromDomain(s.Clocks),
+	}
+}
+
+// Turn is a turn played in the game. Route is written in the text notation,
+// e.g. "E2-E4" or "A7-A8/queen".
+type Turn struct {
+	Number int
+	Color  string
+	Route  string
+	Events []TurnEvent
+}
+
+func TurnsFromDomain(turns []game.Turn) []Turn {
+	result := make([]Turn, 0, len(turns))
+	for _, turn := range turns {
+		result = append(result, TurnFromDomain(turn))
+	}
+	return result
+}
+
+func TurnFromDomain(turn game.Turn) Turn {
+	return Turn{
+		Number: turn.Number,
+		Color:  turn.Color.String(),
+		Route:  notation.FormatRoute(turn.Route),
+		Events: turnEventsFromDomain(turn.Events),
 	}
 }
 
+// TurnEvent is a change made to the board during a turn. Type is one of
+// "PieceMoved" (with From and To), "PiecePlaced" and "PieceRemoved" (with
+// Square) or "PieceCaptured" (with CapturedBy).
+type TurnEvent struct {
+	Type       string
+	PieceType  string
+	Color      string
+	From       *Square `json:",omitempty"`
+	To         *Square `json:",omitempty"`
+	Square     *Square `json:",omitempty"`
+	CapturedBy string  `json:",omitempty"`
+}
+
+func turnEventsFromDomain(events []game.TurnEvent) []TurnEvent {
+	result := make([]TurnEvent, 0, len(events))
+	for _, ev := range events {
+		switch e := ev.(type) {
+		case game.PieceMoved:
+			from, to := squareFromDomain(e.From), squareFromDomain(e.To)
+			result = append(result, TurnEvent{
+				Type:      "PieceMoved",
+				PieceType: e.Piece.Type,
+				Color:     e.Piece.Color.String(),
+				From:      &from,
+				To:        &to,
+			})
+		case game.PiecePlaced:
+			square := squareFromDomain(e.Square)
+			result = append(result, TurnEvent{
+				Type:      "PiecePlaced",
+				PieceType: e.Piece.Type,
+				Color:     e.Piece.Color.String(),
+				Square:    &square,
+			})
+		case game.PieceRemoved:
+			square := squareFromDomain(e.Square)
+			result = append(result, TurnEvent{
+				Type:      "PieceRemoved",
+				PieceType: e.Piece.Type,
+				Color:     e.Piece.Color.String(),
+				Square:    &square,
+			})
+		case game.PieceCaptured:
+			result = append(result, TurnEvent{
+				Type:       "PieceCaptured",
+				PieceType:  e.Piece.Type,
+				Color:      e.Piece.Color.String(),
+				CapturedBy: e.CapturedBy.String(),
+			})
+		}
+	}
+	return result
+}
+
 type Piece struct {
 	Type   PieceType
 	Color  string
@@ -87,13 +291,15 @@ type PieceType struct {
 }
 
 func pieceTypeFromDomain(pieceType *mess.PieceType) PieceType {
-	return PieceType{
-		Name: pieceType.Name(),
-		Presentation: map[string]Presentation{
-			color.Black.String(): presentationFromDomain(pieceType.Presentation(color.Black)),
-			color.White.String(): presentationFromDomain(pieceType.Presentation(color.White)),
-		},
+	presentations := pieceType.Presentations()
+	result := PieceType{
+		Name:         pieceType.Name(),
+		Presentation: make(map[string]Presentation, len(presentations)),
+	}
+	for color, presentation := range presentations {
+		result.Presentation[color.String()] = presentationFromDomain(presentation)
 	}
+	return result
 }
 
 type Presentation struct {
@@ -112,6 +318,14 @@ func presentationFromDomain(presentation mess.Presentation) Presentation {
 
 type Square [2]int
 
+func squaresFromDomain(squares []board.Square) []Square {
+	result := make([]Square, 0, len(squares))
+	for _, square := range squares {
+		result = append(result, squareFromDomain(square))
+	}
+	return result
+}
+
 func squareFromDomain(square board.Square) Square {
 	x, y := square.ToCoords()
 	return [2]int{x, y}
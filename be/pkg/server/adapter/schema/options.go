@@ -150,16 +150,24 @@ func (r RouteBinding) Bind(req *http.Request, obj any) error {
 		return fmt.Errorf("%v excpects a *Route object to bind to, not %T", r.Name(), obj)
 	}
 
-	var parsedSlice []any
 	bytes, err := io.ReadAll(req.Body)
 	if err != nil {
 		return err
 	}
-	err = json.Unmarshal(bytes, &parsedSlice)
+	*result, err = UnmarshalRoute(bytes)
+	return err
+}
+
+// UnmarshalRoute decodes the route from JSON, choosing the type of each option
+// by its Type field.
+func UnmarshalRoute(bytes []byte) (Route, error) {
+	var parsedSlice []any
+	err := json.Unmarshal(bytes, &parsedSlice)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	result := make(Route, 0, len(parsedSlice))
 	for _, parsed := range parsedSlice {
 		var tmpOption struct {
 			Type string
@@ -167,7 +175,7 @@ func (r RouteBinding) Bind(req *http.Request, obj any) error {
 		}
 		err = mapstructure.Decode(parsed, &tmpOption)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		var option Option
 		switch tmpOption.Type {
@@ -181,11 +189,11 @@ func (r RouteBinding) Bind(req *http.Request, obj any) error {
 			option, err = decodeOpton[UnitOption](tmpOption.Rest)
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
-		*result = append(*result, option)
+		result = append(result, option)
 	}
-	return nil
+	return result, nil
 }
 
 func decodeOpton[T any](obj any) (result T, err error) {
@@ -9,18 +9,32 @@ type Room struct {
 	ID            uuid.UUID
 	Players       int
 	PlayersNeeded int
+	Spectators    int
 	IsStartable   bool
 	IsStarted     bool
+	HasComputer   bool
 	RulesFilename string
+	TimeControl   TimeControl
+	// Setup is the position the game starts from, in the setup notation.
+	// Empty if it starts from the initial state of the rules.
+	Setup string
+}
+
+type RoomSetup struct {
+	Setup string
 }
 
 func RoomFromDomain(r *room.Room) *Room {
 	return &Room{
 		ID:            r.ID().UUID,
 		Players:       len(r.Players()),
-		PlayersNeeded: room.PlayersNeeded,
+		PlayersNeeded: r.PlayersNeeded(),
+		Spectators:    len(r.Spectators()),
 		IsStartable:   r.IsStartable(),
 		IsStarted:     r.IsStarted(),
+		HasComputer:   !r.Computer().IsZero(),
 		RulesFilename: r.RulesFile.Filename,
+		TimeControl:   TimeControlFromDomain(r.TimeControl()),
+		Setup:         r.Setup(),
 	}
 }
@@ -0,0 +1,52 @@
+package schema
+
+import "github.com/hashicorp/hcl/v2"
+
+// Diagnostic is a problem found in a rules file. Severity is either error or
+// warning. Range is nil if the problem doesn't concern any part of the source.
+type Diagnostic struct {
+	Severity string
+	Summary  string
+	Detail   string
+	Range    *Range `json:",omitempty"`
+}
+
+// Range spans from Start inclusive to End exclusive.
+type Range struct {
+	Start Pos
+	End   Pos
+}
+
+// Pos contains the 1-based line and column and the 0-based byte offset.
+type Pos struct {
+	Line   int
+	Column int
+	Byte   int
+}
+
+func DiagnosticsFromDomain(diags hcl.Diagnostics) []Diagnostic {
+	result := make([]Diagnostic, 0, len(diags))
+	for _, diag := range diags {
+		severity := "error"
+		if diag.Severity == hcl.DiagWarning {
+			severity = "warning"
+		}
+		result = append(result, Diagnostic{
+			Severity: severity,
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+			Range:    rangeFromDomain(diag.Subject),
+		})
+	}
+	return result
+}
+
+func rangeFromDomain(rng *hcl.Range) *Range {
+	if rng == nil {
+		return nil
+	}
+	return &Range{
+		Start: Pos(rng.Start),
+		End:   Pos(rng.End),
+	}
+}
@@ -0,0 +1,126 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/jostrzol/mess/pkg/server/core/usrerr"
+)
+
+//go:generate enumer -type=Kind -transform=snake
+type Kind int
+
+const (
+	// None means that the game is played without a clock.
+	None Kind = iota
+	// SuddenDeath gives each player a fixed amount of time for the whole game.
+	SuddenDeath
+	// Fischer adds an increment to the player's time after each turn.
+	Fischer
+	// Bronstein doesn't deduct the time spent on a turn up to the delay.
+	Bronstein
+	// ByoYomi gives the player a number of overtime periods after the base
+	// time runs out. A period is used up only if the turn doesn't fit in it.
+	ByoYomi
+)
+
+type TimeControl struct {
+	Kind      Kind
+	Base      time.Duration
+	Increment time.Duration
+	Delay     time.Duration
+	Period    time.Duration
+	Periods   int
+}
+
+// Clock is the time of a single player.
+type Clock struct {
+	Remaining time.Duration
+	Periods   int
+}
+
+func (tc TimeControl) IsEnabled() bool {
+	return tc.Kind != None
+}
+
+func (tc TimeControl) Validate() error {
+	switch {
+	case !tc.Kind.IsAKind():
+		return ErrUnknownKind
+	case !tc.IsEnabled():
+		return nil
+	case tc.Base <= 0:
+		return ErrBaseNotPositive
+	case tc.Kind == Fischer && tc.Increment <= 0:
+		return ErrIncrementNotPositive
+	case tc.Kind == Bronstein && tc.Delay <= 0:
+		return ErrDelayNotPositive
+	case tc.Kind == ByoYomi && (tc.Period <= 0 || tc.Periods <= 0):
+		return ErrPeriodsNotPositive
+	}
+	return nil
+}
+
+// NewClock returns the clock of a player at the start of the game.
+func (tc TimeControl) NewClock() Clock {
+	return Clock{Remaining: tc.Base, Periods: tc.Periods}
+}
+
+// TimeLeft returns how long the player can still think over the current turn,
+// given that elapsed time has already passed since it started. The player
+// runs out of time when it drops to zero.
+func (tc TimeControl) TimeLeft(c Clock, elapsed time.Duration) time.Duration {
+	left := c.Remaining - elapsed
+	switch tc.Kind {
+	case Bronstein:
+		left += tc.Delay
+	case ByoYomi:
+		left += time.Duration(c.Periods) * tc.Period
+	}
+	return left
+}
+
+func (tc TimeControl) IsTimeout(c Clock, elapsed time.Duration) bool {
+	return tc.IsEnabled() && tc.TimeLeft(c, elapsed) <= 0
+}
+
+// Run returns the clock after it has been running for the elapsed time
+// during the current turn.
+func (tc TimeControl) Run(c Clock, elapsed time.Duration) Clock {
+	if tc.Kind == Bronstein {
+		elapsed = max(0, elapsed-tc.Delay)
+	}
+
+	if elapsed <= c.Remaining {
+		c.Remaining -= elapsed
+		return c
+	}
+
+	overtime := elapsed - c.Remaining
+	c.Remaining = 0
+	if tc.Kind == ByoYomi {
+		c.Periods = max(0, c.Periods-int(overtime/tc.Period))
+	}
+	return c
+}
+
+// Spend returns the clock after the player ended a turn lasting elapsed time.
+func (tc TimeControl) Spend(c Clock, elapsed time.Duration) Clock {
+	c = tc.Run(c, elapsed)
+	if tc.Kind == Fischer {
+		c.Remaining += tc.Increment
+	}
+	return c
+}
+
+func max[T int | time.Duration](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+var ErrUnknownKind = usrerr.Errorf("unknown time control")
+var ErrBaseNotPositive = usrerr.Errorf("base time must be positive")
+var ErrIncrementNotPositive = usrerr.Errorf("increment must be positive")
+var ErrDelayNotPositive = usrerr.Errorf("delay must be positive")
+var ErrPeriodsNotPositive = usrerr.Errorf("byo-yomi periods must be positive")
@@ -0,0 +1,124 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpend(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeControl clock.TimeControl
+		clock       clock.Clock
+		elapsed     time.Duration
+		expected    clock.Clock
+	}{
+		{
+			name:        "sudden death",
+			timeControl: clock.TimeControl{Kind: clock.SuddenDeath, Base: time.Minute},
+			clock:       clock.Clock{Remaining: time.Minute},
+			elapsed:     10 * time.Second,
+			expected:    clock.Clock{Remaining: 50 * time.Second},
+		},
+		{
+			name:        "fischer",
+			timeControl: clock.TimeControl{Kind: clock.Fischer, Base: time.Minute, Increment: 2 * time.Second},
+			clock:       clock.Clock{Remaining: time.Minute},
+			elapsed:     10 * time.Second,
+			expected:    clock.Clock{Remaining: 52 * time.Second},
+		},
+		{
+			name:        "bronstein within delay",
+			timeControl: clock.TimeControl{Kind: clock.Bronstein, Base: time.Minute, Delay: 5 * time.Second},
+			clock:       clock.Clock{Remaining: time.Minute},
+			elapsed:     3 * time.Second,
+			expected:    clock.Clock{Remaining: time.Minute},
+		},
+		{
+			name:        "bronstein over delay",
+			timeControl: clock.TimeControl{Kind: clock.Bronstein, Base: time.Minute, Delay: 5 * time.Second},
+			clock:       clock.Clock{Remaining: time.Minute},
+			elapsed:     10 * time.Second,
+			expected:    clock.Clock{Remaining: 55 * time.Second},
+		},
+		{
+			name:        "byo-yomi within period",
+			timeControl: clock.TimeControl{Kind: clock.ByoYomi, Base: time.Minute, Period: 10 * time.Second, Periods: 3},
+			clock:       clock.Clock{Remaining: 0, Periods: 3},
+			elapsed:     9 * time.Second,
+			expected:    clock.Clock{Remaining: 0, Periods: 3},
+		},
+		{
+			name:        "byo-yomi over periods",
+			timeControl: clock.TimeControl{Kind: clock.ByoYomi, Base: time.Minute, Period: 10 * time.Second, Periods: 3},
+			clock:       clock.Clock{Remaining: 5 * time.Second, Periods: 3},
+			elapsed:     26 * time.Second,
+			expected:    clock.Clock{Remaining: 0, Periods: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.timeControl.Spend(tt.clock, tt.elapsed)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.False(t, tt.timeControl.IsTimeout(tt.clock, tt.elapsed))
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeControl clock.TimeControl
+		clock       clock.Clock
+		elapsed     time.Duration
+	}{
+		{
+			name:        "sudden death",
+			timeControl: clock.TimeControl{Kind: clock.SuddenDeath, Base: time.Minute},
+			clock:       clock.Clock{Remaining: time.Minute},
+			elapsed:     time.Minute,
+		},
+		{
+			name:        "bronstein",
+			timeControl: clock.TimeControl{Kind: clock.Bronstein, Base: time.Minute, Delay: 5 * time.Second},
+			clock:       clock.Clock{Remaining: time.Second},
+			elapsed:     6 * time.Second,
+		},
+		{
+			name:        "byo-yomi",
+			timeControl: clock.TimeControl{Kind: clock.ByoYomi, Base: time.Minute, Period: 10 * time.Second, Periods: 3},
+			clock:       clock.Clock{Remaining: 0, Periods: 2},
+			elapsed:     20 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.timeControl.IsTimeout(tt.clock, tt.elapsed))
+		})
+	}
+}
+
+func TestNoneNeverTimesOut(t *testing.T) {
+	timeControl := clock.TimeControl{}
+
+	assert.False(t, timeControl.IsTimeout(timeControl.NewClock(), time.Hour))
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]clock.TimeControl{
+		"unknown kind":       {Kind: clock.Kind(-1)},
+		"no base":            {Kind: clock.SuddenDeath},
+		"no increment":       {Kind: clock.Fischer, Base: time.Minute},
+		"no delay":           {Kind: clock.Bronstein, Base: time.Minute},
+		"no byo-yomi period": {Kind: clock.ByoYomi, Base: time.Minute, Periods: 3},
+	}
+	for name, timeControl := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, timeControl.Validate())
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package event
 
 import (
+	"time"
+
 	"github.com/golobby/container/v3"
+	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/event"
 	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
 	"github.com/jostrzol/mess/pkg/server/core/id"
 )
 
@@ -16,22 +20,104 @@ type PlayerJoined struct {
 	PlayerID id.Session
 }
 
+type SpectatorJoined struct {
+	RoomID      id.Room
+	SpectatorID id.Session
+}
+
 type RoomRulesChanged struct {
 	RoomID id.Room
 	By     id.Session
 }
 
+type RoomTimeControlChanged struct {
+	RoomID id.Room
+	By     id.Session
+}
+
+type RoomSetupChanged struct {
+	RoomID id.Room
+	By     id.Session
+}
+
+type ChatMessageSent struct {
+	RoomID id.Room
+	By     id.Session
+	Text   string
+	Time   time.Time
+}
+
 type GameStarted struct {
-	GameID  id.Game
-	RoomID  id.Room
-	Players [2]id.Session
-	Rules   *rules.File
-	By      id.Session
+	GameID      id.Game
+	RoomID      id.Room
+	Players     map[color.Color]id.Session
+	Rules       *rules.File
+	TimeControl clock.TimeControl
+	Setup       string
+	Computer    id.Session
+	By          id.Session
+}
+
+// GameCreated is notified once the game requested by GameStarted is ready to
+// be played.
+type GameCreated struct {
+	GameID id.Game
+	RoomID id.Room
+	By     id.Session
 }
 
 type GameChanged struct {
 	GameID id.Game
 	By     id.Session
+	// Turn is the number of the turn played, if the game changed because of
+	// playing one.
+	Turn *int
+}
+
+// GameRestored is notified when a stored game is loaded back into memory,
+// e.g. after a restart of the server.
+type GameRestored struct {
+	GameID id.Game
+}
+
+type GameTimedOut struct {
+	GameID id.Game
+	Player id.Session
+}
+
+type TakebackRequested struct {
+	GameID id.Game
+	By     id.Session
+	Turns  int
+}
+
+// TakebackAccepted is notified when a player accepts a takeback, which still
+// awaits the answers of the other players.
+type TakebackAccepted struct {
+	GameID id.Game
+	By     id.Session
+}
+
+type TakebackDeclined struct {
+	GameID id.Game
+	By     id.Session
+}
+
+type DrawOffered struct {
+	GameID id.Game
+	By     id.Session
+}
+
+// DrawAccepted is notified when a player accepts a draw offer, which still
+// awaits the answers of the other players.
+type DrawAccepted struct {
+	GameID id.Game
+	By     id.Session
+}
+
+type DrawDeclined struct {
+	GameID id.Game
+	By     id.Session
 }
 
 type Broker struct {
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"time"
+
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/event"
+	"golang.org/x/exp/maps"
+)
+
+func (g *Game) TimeControl() clock.TimeControl {
+	return g.timeControl
+}
+
+// TimeLeft returns how long the current player can still think before
+// running out of time. Returns false if the game is played without a clock
+// or has already been resolved.
+func (g *Game) TimeLeft() (time.Duration, bool) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	if !g.timeControl.IsEnabled() || !g.timedOut.IsZero() || g.isOver() {
+		return 0, false
+	}
+	color := g.game.CurrentPlayer().Color()
+	return g.timeControl.TimeLeft(g.clocks[color], time.Since(g.turnStart)), true
+}
+
+// CheckTimeout resolves the game if the current player has run out of time.
+// The resulting event is returned only once, when the timeout is detected.
+func (g *Game) CheckTimeout() event.Event {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	if !g.timedOut.IsZero() || !g.isTimeout(time.Now()) {
+		return nil
+	}
+	g.timedOut = g.CurrentPlayer()
+	return &event.GameTimedOut{
+		GameID: g.id,
+		Player: g.timedOut,
+	}
+}
+
+// isTimeout checks if the current player has run out of time.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) isTimeout(now time.Time) bool {
+	if !g.timedOut.IsZero() {
+		return true
+	} else if g.isOver() {
+		// the clocks are stopped once the game ends
+		return false
+	}
+	color := g.game.CurrentPlayer().Color()
+	return g.timeControl.IsTimeout(g.clocks[color], now.Sub(g.turnStart))
+}
+
+// timeoutResolution presumes that the current player has run out of time.
+// In a two-player game the opponent wins, otherwise nobody does.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) timeoutResolution() *Resolution {
+	loser := g.game.CurrentPlayer()
+	result := &Resolution{
+		IsResolved: true,
+		Players:    g.Players(),
+		Loser:      g.players[loser.Color()],
+		Reason:     mess.Timeout,
+	}
+	if len(g.players) == 2 {
+		winnerColor := g.game.CurrentOpponent().Color()
+		result.Winner = g.players[winnerColor]
+		result.WinnerColor = winnerColor
+	}
+	return result
+}
+
+// currentClocks returns the players' clocks with the current player's one
+// running since the start of the turn.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) currentClocks(now time.Time) map[color.Color]clock.Clock {
+	clocks := maps.Clone(g.clocks)
+	if g.isOver() {
+		// the clocks are stopped once the game ends
+		return clocks
+	}
+	color := g.game.CurrentPlayer().Color()
+	clocks[color] = g.timeControl.Run(clocks[color], now.Sub(g.turnStart))
+	return clocks
+}
+
+// spendTurn stops the clock of the player of the given color, who has just
+// ended their turn, and starts the next turn's clock.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) spendTurn(color color.Color, now time.Time) {
+	if g.timeControl.IsEnabled() {
+		g.clocks[color] = g.timeControl.Spend(g.clocks[color], now.Sub(g.turnStart))
+	}
+	g.turnStart = now
+}
+
+// runClock charges the current player for the time spent so far and restarts
+// the turn's clock, without ending the turn.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) runClock(now time.Time) {
+	if g.timeControl.IsEnabled() {
+		color := g.game.CurrentPlayer().Color()
+		g.clocks[color] = g.timeControl.Run(g.clocks[color], now.Sub(g.turnStart))
+	}
+	g.turnStart = now
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golobby/container/v3"
+	"github.com/jostrzol/mess/configs/serverconfig"
+	"github.com/jostrzol/mess/pkg/engine"
+	"github.com/jostrzol/mess/pkg/server/core/event"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"golang.org/x/exp/slices"
+)
+
+func init() {
+	container.MustSingletonLazy(container.Global, func(config *serverconfig.Config) engine.Engine {
+		return &engine.Minimax{Depth: config.ComputerDepth}
+	})
+}
+
+// Computer returns the session of the player controlled by the computer,
+// or a zero session if there is none.
+func (g *Game) Computer() id.Session {
+	return g.computer
+}
+
+func (g *Game) IsComputerTurn() bool {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	return g.isComputerTurn()
+}
+
+// isComputerTurn presumes that THE MUTEX IS LOCKED!
+func (g *Game) isComputerTurn() bool {
+	return !g.computer.IsZero() && g.CurrentPlayer() == g.computer
+}
+
+// PlayComputerTurn plays the turn chosen by the engine for the computer player.
+// The engine searches a replayed copy of the game, so that the game is not
+// locked during the search.
+func (g *Game) PlayComputerTurn(e engine.Engine) (event.Event, error) {
+	g.mutex.Lock()
+	if !g.isComputerTurn() {
+		g.mutex.Unlock()
+		return nil, ErrNotComputerTurn
+	} else if g.isOver() {
+		g.mutex.Unlock()
+		return nil, ErrGameEnded
+	}
+	rulesFile, setup, routes := g.rules, g.setup, slices.Clone(g.routes)
+	turn, hash := g.game.TurnNumber(), g.game.Hash()
+	g.mutex.Unlock()
+
+	game, err := replay(rulesFile, setup, turn-len(routes), routes)
+	if err != nil {
+		return nil, fmt.Errorf("copying game: %w", err)
+	}
+	route, err := e.BestRoute(game)
+	if err != nil {
+		return nil, fmt.Errorf("choosing route: %w", err)
+	}
+
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	// the game could have changed during the search
+	if g.game.TurnNumber() != turn || g.game.Hash() != hash || g.isOver() {
+		return nil, ErrComputerTurnOutdated
+	}
+	route, err = rebindRoute(route, g.cachedPieceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("rebinding route: %w", err)
+	}
+
+	now := time.Now()
+	if g.isTimeout(now) {
+		return nil, ErrTimeout
+	}
+
+	color := g.game.CurrentPlayer().Color()
+	events, err := playTurn(g.game, route)
+	if err != nil {
+		return nil, fmt.Errorf("playing route: %w", err)
+	}
+
+	g.spendTurn(color, now)
+	g.routes = append(g.routes, route)
+	g.turnEvents = append(g.turnEvents, events)
+	g.takeback = nil
+	g.declineDrawOffer(g.computer)
+	g.calculateState()
+	return &event.GameChanged{
+		GameID: g.id,
+		By:     g.computer,
+		Turn:   &turn,
+	}, nil
+}
+
+var ErrNotComputerTurn = fmt.Errorf("it's not the computer's turn")
+var ErrComputerTurnOutdated = fmt.Errorf("the game changed while the computer was choosing its turn")
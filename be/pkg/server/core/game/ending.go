@@ -0,0 +1,176 @@
+package game
+
+import (
+	"time"
+
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/server/core/event"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/usrerr"
+	"golang.org/x/exp/slices"
+)
+
+// Resign ends the game with the defeat of the resigning player. In a
+// two-player game the opponent wins, otherwise nobody does.
+func (g *Game) Resign(session id.Session) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	now := time.Now()
+	if err := g.assertCanEnd(session, now); err != nil {
+		return nil, err
+	}
+
+	resolution := &Resolution{
+		IsResolved: true,
+		Players:    g.Players(),
+		Loser:      session,
+		Reason:     mess.Resignation,
+	}
+	if len(g.players) == 2 {
+		color, _ := g.playerColor(session)
+		winnerColor := g.game.OpponentTo(g.game.Player(color)).Color()
+		resolution.Winner = g.players[winnerColor]
+		resolution.WinnerColor = winnerColor
+	}
+	g.end(resolution, now)
+	return &event.GameChanged{
+		GameID: g.id,
+		By:     session,
+	}, nil
+}
+
+func (g *Game) DrawOffer() *DrawOffer {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	return g.drawOffer
+}
+
+// OfferDraw offers the other players to end the game in a draw. The offer is
+// implicitly declined when any of them plays a turn.
+func (g *Game) OfferDraw(session id.Session) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	if err := g.assertCanEnd(session, time.Now()); err != nil {
+		return nil, err
+	} else if g.drawOffer != nil {
+		return nil, ErrDrawOfferPending
+	}
+
+	g.drawOffer = &DrawOffer{By: session}
+	return &event.DrawOffered{
+		GameID: g.id,
+		By:     session,
+	}, nil
+}
+
+// AnswerDrawOffer accepts or declines the pending draw offer. A single
+// decline rejects the offer.
+func (g *Game) AnswerDrawOffer(session id.Session, accept bool) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	now := time.Now()
+	switch {
+	case !g.isPlayer(session):
+		return nil, ErrNotAPlayer
+	case g.drawOffer == nil:
+		return nil, ErrNoDrawOffer
+	case g.drawOffer.By == session:
+		return nil, ErrOwnDrawOffer
+	}
+	if err := g.assertCanEnd(session, now); err != nil {
+		return nil, err
+	}
+
+	if !accept {
+		g.drawOffer = nil
+		return &event.DrawDeclined{
+			GameID: g.id,
+			By:     session,
+		}, nil
+	} else if slices.Contains(g.drawOffer.AcceptedBy, session) {
+		return nil, ErrDrawOfferAccepted
+	}
+
+	g.drawOffer.AcceptedBy = append(g.drawOffer.AcceptedBy, session)
+	if len(g.drawOffer.AcceptedBy) < len(g.players)-1 {
+		return &event.DrawAccepted{
+			GameID: g.id,
+			By:     session,
+		}, nil
+	}
+
+	g.end(&Resolution{
+		IsResolved: true,
+		Players:    g.Players(),
+		Reason:     mess.Agreement,
+	}, now)
+	return &event.GameChanged{
+		GameID: g.id,
+		By:     session,
+	}, nil
+}
+
+// Abort ends the game without any winner or loser. Games can only be aborted
+// before the first turn is played.
+func (g *Game) Abort(session id.Session) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	now := time.Now()
+	if err := g.assertCanEnd(session, now); err != nil {
+		return nil, err
+	} else if len(g.routes) != 0 {
+		return nil, ErrAbortAfterStart
+	}
+
+	g.end(&Resolution{
+		IsResolved: true,
+		Players:    g.Players(),
+		Reason:     mess.Aborted,
+	}, now)
+	return &event.GameChanged{
+		GameID: g.id,
+		By:     session,
+	}, nil
+}
+
+// assertCanEnd presumes that THE MUTEX IS LOCKED!
+func (g *Game) assertCanEnd(session id.Session, now time.Time) error {
+	switch {
+	case !g.isPlayer(session):
+		return ErrNotAPlayer
+	case g.resolution(now).IsResolved:
+		return ErrGameEnded
+	default:
+		return nil
+	}
+}
+
+// end stops the clocks and ends the game with the given resolution.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) end(resolution *Resolution, now time.Time) {
+	g.runClock(now)
+	g.ending = resolution
+	g.takeback = nil
+	g.drawOffer = nil
+	g.calculateState()
+}
+
+// declineDrawOffer declines the pending draw offer, unless it was made by
+// the given player.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) declineDrawOffer(session id.Session) {
+	if g.drawOffer != nil && g.drawOffer.By != session {
+		g.drawOffer = nil
+	}
+}
+
+var ErrDrawOfferPending = usrerr.Errorf("a draw offer is already pending")
+var ErrNoDrawOffer = usrerr.Errorf("no draw offer is pending")
+var ErrOwnDrawOffer = usrerr.Errorf("you cannot answer your own draw offer")
+var ErrDrawOfferAccepted = usrerr.Errorf("you have already accepted the draw offer")
+var ErrAbortAfterStart = usrerr.Errorf("cannot abort a game after the first turn")
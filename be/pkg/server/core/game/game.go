@@ -3,14 +3,19 @@ package game
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/jostrzol/mess/pkg/board"
 	"github.com/jostrzol/mess/pkg/color"
 	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
 	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
 	"github.com/jostrzol/mess/pkg/server/core/event"
 	"github.com/jostrzol/mess/pkg/server/core/id"
 	"github.com/jostrzol/mess/pkg/server/core/usrerr"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type Game struct {
@@ -25,6 +30,27 @@ type Game struct {
 	// Should be accessed through State() method.
 	cachedState      *State
 	cachedPieceTypes map[string]*mess.PieceType
+	takeback         *Takeback
+	computer         id.Session
+	rules            *rules.File
+	// setup is the position the game started from, in the setup notation.
+	// Empty if the game started from the initial state of the rules.
+	setup string
+	// routes contains the routes chosen in all the turns played so far,
+	// so that the game can be replayed from the rules and the setup.
+	routes []mess.Route
+	// turnEvents contains the events of the turns played so far, in the same
+	// order as the routes.
+	turnEvents  [][]TurnEvent
+	timeControl clock.TimeControl
+	// clocks contain the players' times as of the start of the current turn.
+	clocks    map[color.Color]clock.Clock
+	turnStart time.Time
+	timedOut  id.Session
+	drawOffer *DrawOffer
+	// ending is the resolution of a game ended by the players themselves,
+	// e.g. by resigning.
+	ending *Resolution
 }
 
 type State struct {
@@ -33,12 +59,28 @@ type State struct {
 	Board         *mess.PieceBoard
 	PieceTypes    map[string]*mess.PieceType
 	CurrentPlayer id.Session
+	CurrentColor  color.Color
+	// Setup is the position in the setup notation. Empty if the position
+	// cannot be written in it, e.g. because of ambiguous piece codes.
+	Setup string
+	// Clocks contain the players' times as of the moment the state was
+	// requested. Empty if the game is played without a clock.
+	Clocks map[color.Color]clock.Clock
 }
 
 type StaticData struct {
-	ID        id.Game
-	BoardSize BoardSize
-	MyColor   color.Color
+	ID              id.Game
+	BoardSize       BoardSize
+	DisabledSquares []board.Square
+	Topology        board.Topology
+	Zones           []*mess.Zone
+	MyColor         color.Color
+	// IsSpectator is set if the session only watches the game, in which case
+	// MyColor is meaningless.
+	IsSpectator bool
+	// Colors contains the colors of all the players in their turn order.
+	Colors      []color.Color
+	TimeControl clock.TimeControl
 }
 
 type BoardSize struct {
@@ -49,29 +91,88 @@ type BoardSize struct {
 type Resolution struct {
 	IsResolved bool
 	Winner     id.Session
+	// WinnerColor is only meaningful if there is a winner.
+	WinnerColor color.Color
+	// Players contains everyone taking part in the game, so that the
+	// resolution can be presented to the spectators.
+	Players []id.Session
+	// Loser is the player who lost by running out of time or resigning, if any.
+	Loser  id.Session
+	Reason mess.EndReason
+}
+
+type Takeback struct {
+	By    id.Session
+	Turns int
+	// AcceptedBy are the players who have accepted the takeback so far. The
+	// turns are taken back once all the other players accept it.
+	AcceptedBy []id.Session
+}
+
+type DrawOffer struct {
+	By id.Session
+	// AcceptedBy are the players who have accepted the offer so far. The game
+	// ends in a draw once all the other players accept it.
+	AcceptedBy []id.Session
 }
 
 func New(event *event.GameStarted) (*Game, error) {
-	game, err := rules.DecodeRules(event.Rules, true)
+	players := maps.Clone(event.Players)
+	return newGame(event.GameID, event.RoomID, players, event.Computer, event.Rules, event.Setup, event.TimeControl)
+}
+
+func newGame(
+	gameID id.Game,
+	roomID id.Room,
+	players map[color.Color]id.Session,
+	computer id.Session,
+	rulesFile *rules.File,
+	setup string,
+	timeControl clock.TimeControl,
+) (*Game, error) {
+	game, err := decodeGame(rulesFile, setup)
 	if err != nil {
-		return nil, fmt.Errorf("decoding rules: %w", err)
+		return nil, err
 	}
 	result := &Game{
-		id:   event.GameID,
-		room: event.RoomID,
-		players: map[color.Color]id.Session{
-			color.White: event.Players[color.White],
-			color.Black: event.Players[color.Black],
-		},
+		id:               gameID,
+		room:             roomID,
+		players:          players,
 		mutex:            sync.Mutex{},
 		game:             game,
 		cachedPieceTypes: game.PieceTypesByName(),
+		computer:         computer,
+		rules:            rulesFile,
+		setup:            setup,
+		timeControl:      timeControl,
+		clocks:           make(map[color.Color]clock.Clock),
+		turnStart:        time.Now(),
+	}
+	if timeControl.IsEnabled() {
+		for _, player := range game.Players() {
+			result.clocks[player.Color()] = timeControl.NewClock()
+		}
 	}
 	result.calculateState()
 
 	return result, nil
 }
 
+func decodeGame(rulesFile *rules.File, setup string) (*mess.Game, error) {
+	if setup == "" {
+		game, err := rules.DecodeRules(rulesFile, true)
+		if err != nil {
+			return nil, fmt.Errorf("decoding rules: %w", err)
+		}
+		return game, nil
+	}
+	game, err := rules.DecodeRulesWithSetup(rulesFile, setup)
+	if err != nil {
+		return nil, fmt.Errorf("decoding rules with setup: %w", err)
+	}
+	return game, nil
+}
+
 func (g *Game) ID() id.Game {
 	return g.id
 }
@@ -89,13 +190,29 @@ func (g *Game) CurrentPlayer() id.Session {
 }
 
 func (g *Game) StaticData(session id.Session) *StaticData {
+	myColor, isPlayer := g.playerColor(session)
 	return &StaticData{
-		ID:        g.id,
-		BoardSize: g.boardSize(),
-		MyColor:   g.playerColor(session),
+		ID:              g.id,
+		BoardSize:       g.boardSize(),
+		DisabledSquares: g.game.Board().DisabledSquares(),
+		Topology:        g.game.Board().Topology(),
+		Zones:           g.game.Zones(),
+		MyColor:         myColor,
+		IsSpectator:     !isPlayer,
+		Colors:          g.colors(),
+		TimeControl:     g.timeControl,
 	}
 }
 
+func (g *Game) colors() []color.Color {
+	players := g.game.Players()
+	result := make([]color.Color, 0, len(players))
+	for _, player := range players {
+		result = append(result, player.Color())
+	}
+	return result
+}
+
 func (g *Game) boardSize() BoardSize {
 	width, height := g.game.Board().Size()
 	return BoardSize{
@@ -104,26 +221,39 @@ func (g *Game) boardSize() BoardSize {
 	}
 }
 
-func (g *Game) playerColor(session id.Session) color.Color {
+func (g *Game) playerColor(session id.Session) (color.Color, bool) {
 	for color, player := range g.players {
 		if player == session {
-			return color
+			return color, true
 		}
 	}
-	panic(fmt.Errorf("no color for player %v", session))
+	return 0, false
 }
 
 func (g *Game) State() *State {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
 	if g.cachedState == nil {
 		panic(fmt.Errorf("state not calculated"))
 	}
-	return g.cachedState
+	if !g.timeControl.IsEnabled() {
+		return g.cachedState
+	}
+
+	state := *g.cachedState
+	state.Clocks = g.currentClocks(time.Now())
+	return &state
 }
 
 func (g *Game) TurnOptions() (*mess.OptionNode, error) {
 	g.mutex.Lock()
 	defer func() { g.mutex.Unlock() }()
 
+	if g.isOver() {
+		return nil, ErrGameEnded
+	}
+
 	optionTree, err := g.game.TurnOptions()
 	if err != nil {
 		return nil, fmt.Errorf("generating turn options: %w", err)
@@ -136,7 +266,12 @@ func (g *Game) PlayTurn(session id.Session, turn int, route mess.Route) (event.E
 	g.mutex.Lock()
 	defer func() { g.mutex.Unlock() }()
 
-	if g.CurrentPlayer() != session {
+	switch {
+	case !g.isPlayer(session):
+		return nil, ErrNotAPlayer
+	case g.isOver():
+		return nil, ErrGameEnded
+	case g.CurrentPlayer() != session:
 		return nil, ErrNotYourTurn
 	}
 
@@ -150,27 +285,218 @@ func (g *Game) PlayTurn(session id.Session, turn int, route mess.Route) (event.E
 		return nil, ErrTurnTooBig
 	}
 
-	err := g.game.PlayTurn(route)
+	now := time.Now()
+	if g.isTimeout(now) {
+		return nil, ErrTimeout
+	}
+
+	color := g.game.CurrentPlayer().Color()
+	events, err := playTurn(g.game, route)
 	if err != nil {
 		return nil, usrerr.Errorf("choosing turn options: %w", err)
 	}
 
+	g.spendTurn(color, now)
+	g.routes = append(g.routes, route)
+	g.turnEvents = append(g.turnEvents, events)
+	// playing a turn implicitly declines the pending takeback
+	g.takeback = nil
+	g.declineDrawOffer(session)
 	g.calculateState()
 	return &event.GameChanged{
 		GameID: g.id,
 		By:     session,
+		Turn:   &currentTurn,
+	}, nil
+}
+
+func (g *Game) Takeback() *Takeback {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	return g.takeback
+}
+
+func (g *Game) RequestTakeback(session id.Session, turns int) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	switch {
+	case !g.isPlayer(session):
+		return nil, ErrNotAPlayer
+	case g.ending != nil:
+		return nil, ErrGameEnded
+	case g.takeback != nil:
+		return nil, ErrTakebackPending
+	case turns <= 0:
+		return nil, ErrTakebackNoTurns
+	case turns > len(g.routes):
+		return nil, ErrTakebackTooManyTurns
+	}
+
+	g.takeback = &Takeback{By: session, Turns: turns}
+	return &event.TakebackRequested{
+		GameID: g.id,
+		By:     session,
+		Turns:  turns,
 	}, nil
 }
 
+// AnswerTakeback accepts or declines the pending takeback. A single decline
+// rejects the takeback.
+func (g *Game) AnswerTakeback(session id.Session, accept bool) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	switch {
+	case !g.isPlayer(session):
+		return nil, ErrNotAPlayer
+	case g.takeback == nil:
+		return nil, ErrNoTakeback
+	case g.takeback.By == session:
+		return nil, ErrOwnTakeback
+	}
+
+	takeback := g.takeback
+	if !accept {
+		g.takeback = nil
+		return &event.TakebackDeclined{
+			GameID: g.id,
+			By:     session,
+		}, nil
+	} else if slices.Contains(takeback.AcceptedBy, session) {
+		return nil, ErrTakebackAccepted
+	}
+
+	now := time.Now()
+	if g.isTimeout(now) {
+		return nil, ErrTimeout
+	}
+	takeback.AcceptedBy = append(takeback.AcceptedBy, session)
+	if len(takeback.AcceptedBy) < len(g.players)-1 {
+		return &event.TakebackAccepted{
+			GameID: g.id,
+			By:     session,
+		}, nil
+	}
+
+	// the time spent so far is charged, but without ending the turn
+	g.runClock(now)
+
+	for i := 0; i < takeback.Turns; i++ {
+		err := g.game.RevertTurn()
+		if err != nil {
+			return nil, fmt.Errorf("reverting turn: %w", err)
+		}
+	}
+	g.routes = g.routes[:len(g.routes)-takeback.Turns]
+	g.turnEvents = g.turnEvents[:len(g.turnEvents)-takeback.Turns]
+	g.takeback = nil
+
+	g.calculateState()
+	return &event.GameChanged{
+		GameID: g.id,
+		By:     session,
+	}, nil
+}
+
+// Export records the game in the notation.GameFile format.
+func (g *Game) Export() *notation.GameFile {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	file := notation.NewGameFile(g.rules, g.routes)
+	if g.setup != "" {
+		file.SetHeader(notation.HeaderSetup, g.setup)
+	}
+	return file
+}
+
+// Import replays all the turns recorded in the game file, starting from its
+// setup if it has one. Turns can only be imported before the first turn is
+// played.
+func (g *Game) Import(session id.Session, file *notation.GameFile) (event.Event, error) {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	switch {
+	case !g.isPlayer(session):
+		return nil, ErrNotAPlayer
+	case g.ending != nil:
+		return nil, ErrGameEnded
+	case len(g.routes) != 0:
+		return nil, ErrImportAfterStart
+	}
+
+	err := file.CheckRules(g.rules)
+	if err != nil {
+		return nil, usrerr.Errorf("importing game: %w", err)
+	}
+
+	setup := g.setup
+	if fileSetup, ok := file.Header(notation.HeaderSetup); ok {
+		setup = fileSetup
+	}
+	// replay on a fresh game, so that a failed import leaves this one untouched
+	game, err := decodeGame(g.rules, setup)
+	if err != nil {
+		return nil, usrerr.Errorf("importing game: %w", err)
+	}
+	routes, err := file.Replay(game)
+	if err != nil {
+		return nil, usrerr.Errorf("importing game: %w", err)
+	}
+
+	g.game = game
+	g.cachedPieceTypes = game.PieceTypesByName()
+	g.setup = setup
+	g.routes = routes
+	g.turnEvents = recordEvents(game.State.Record())
+	g.takeback = nil
+	g.turnStart = time.Now()
+	g.calculateState()
+	return &event.GameChanged{
+		GameID: g.id,
+		By:     session,
+	}, nil
+}
+
+func (g *Game) isPlayer(session id.Session) bool {
+	for _, player := range g.players {
+		if player == session {
+			return true
+		}
+	}
+	return false
+}
+
+// isOver checks if the game has been ended, either by the players or by the
+// rules. Repetitions and the lack of progress end the game while there are
+// still legal turns, so the rules have to be asked too.
+// Presumes that THE MUTEX IS LOCKED!
+func (g *Game) isOver() bool {
+	return g.ending != nil || g.game.Resolution().DidEnd
+}
+
 // calculateState caches the current game state.
 // Presumes that THE MUTEX IS LOCKED!
 func (g *Game) calculateState() {
-	g.cachedState = &State{
+	g.cachedState = g.stateOf(g.game, g.cachedPieceTypes)
+}
+
+// stateOf returns the state of the given game played by the players of this
+// one, e.g. of this game replayed up to some turn.
+func (g *Game) stateOf(game *mess.Game, pieceTypes map[string]*mess.PieceType) *State {
+	// the rules can make the setup notation ambiguous, then it's left empty
+	setup, _ := game.Setup()
+	return &State{
 		ID:            g.id,
-		TurnNumber:    g.game.TurnNumber(),
-		Board:         g.game.Board().Clone(),
-		CurrentPlayer: g.players[g.game.CurrentPlayer().Color()],
-		PieceTypes:    g.cachedPieceTypes,
+		TurnNumber:    game.TurnNumber(),
+		Board:         game.Board().Clone(),
+		CurrentPlayer: g.players[game.CurrentPlayer().Color()],
+		CurrentColor:  game.CurrentPlayer().Color(),
+		Setup:         setup,
+		PieceTypes:    pieceTypes,
 	}
 }
 
@@ -178,16 +504,31 @@ func (g *Game) Resolution() *Resolution {
 	g.mutex.Lock()
 	defer func() { g.mutex.Unlock() }()
 
-	resolution := g.game.Resolution()
-	var winnerSession id.Session
-	if resolution.Winner != nil {
-		winnerSession = g.players[resolution.Winner.Color()]
+	return g.resolution(time.Now())
+}
+
+// resolution presumes that THE MUTEX IS LOCKED!
+func (g *Game) resolution(now time.Time) *Resolution {
+	if g.ending != nil {
+		ending := *g.ending
+		return &ending
 	}
 
-	return &Resolution{
+	// the rules are checked first, since they stop the clocks
+	resolution := g.game.Resolution()
+	if !resolution.DidEnd && g.isTimeout(now) {
+		return g.timeoutResolution()
+	}
+	result := &Resolution{
 		IsResolved: resolution.DidEnd,
-		Winner:     winnerSession,
+		Players:    g.Players(),
+		Reason:     resolution.Reason,
+	}
+	if resolution.Winner != nil {
+		result.Winner = g.players[resolution.Winner.Color()]
+		result.WinnerColor = resolution.Winner.Color()
 	}
+	return result
 }
 
 func (g *Game) Asset(key mess.AssetKey) []byte {
@@ -205,3 +546,13 @@ func (g *Game) Asset(key mess.AssetKey) []byte {
 var ErrTurnTooSmall = usrerr.Errorf("the selected turn has already been played")
 var ErrTurnTooBig = usrerr.Errorf("the selected turn hasn't started yet")
 var ErrNotYourTurn = usrerr.Errorf("it's not your turn")
+var ErrNotAPlayer = usrerr.Errorf("you are not a player in this game")
+var ErrTakebackPending = usrerr.Errorf("a takeback is already pending")
+var ErrTakebackNoTurns = usrerr.Errorf("at least one turn must be taken back")
+var ErrTakebackTooManyTurns = usrerr.Errorf("not enough turns have been played to take back")
+var ErrNoTakeback = usrerr.Errorf("no takeback is pending")
+var ErrOwnTakeback = usrerr.Errorf("you cannot answer your own takeback")
+var ErrTakebackAccepted = usrerr.Errorf("you have already accepted the takeback")
+var ErrTimeout = usrerr.Errorf("the time is up")
+var ErrGameEnded = usrerr.Errorf("the game has already ended")
+var ErrImportAfterStart = usrerr.Errorf("cannot import a game after the first turn")
@@ -0,0 +1,195 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/jostrzol/mess/pkg/board"
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/usrerr"
+	"golang.org/x/exp/slices"
+)
+
+// Turn is a turn played in the game.
+type Turn struct {
+	// Number is the turn number of the state the turn was played in.
+	Number int
+	Color  color.Color
+	Player id.Session
+	Route  mess.Route
+	Events []TurnEvent
+}
+
+// TurnEvent is a change made to the board during a turn. The pieces are
+// described as they were when the change was made.
+type TurnEvent interface{}
+
+type PieceMoved struct {
+	Piece Piece
+	From  board.Square
+	To    board.Square
+}
+
+type PiecePlaced struct {
+	Piece  Piece
+	Square board.Square
+}
+
+type PieceRemoved struct {
+	Piece  Piece
+	Square board.Square
+}
+
+type PieceCaptured struct {
+	Piece      Piece
+	CapturedBy color.Color
+}
+
+type Piece struct {
+	Type  string
+	Color color.Color
+}
+
+// pieceOf describes the piece, owned by the player given in owners if it's
+// there, or by its current owner otherwise.
+func pieceOf(piece *mess.Piece, owners map[*mess.Piece]*mess.Player) Piece {
+	owner, ok := owners[piece]
+	if !ok {
+		owner = piece.Owner()
+	}
+	return Piece{Type: piece.Type().Name(), Color: owner.Color()}
+}
+
+// turnEvents converts the events of a turn. Only the changes made to the board
+// are kept.
+func turnEvents(turn mess.Turn, owners map[*mess.Piece]*mess.Player) []TurnEvent {
+	result := make([]TurnEvent, 0, len(turn))
+	for _, ev := range turn {
+		switch e := ev.(type) {
+		case mess.PieceMoved:
+			result = append(result, PieceMoved{Piece: pieceOf(e.Piece, owners), From: e.From, To: e.To})
+		case mess.PiecePlaced:
+			result = append(result, PiecePlaced{Piece: pieceOf(e.Piece, owners), Square: e.Square})
+		case mess.PieceRemoved:
+			result = append(result, PieceRemoved{Piece: pieceOf(e.Piece, owners), Square: e.Square})
+		case mess.PieceCaptured:
+			if e.CapturedBy == nil {
+				continue
+			}
+			result = append(result, PieceCaptured{
+				Piece:      Piece{Type: e.Piece.Type().Name(), Color: e.CapturedFrom.Color()},
+				CapturedBy: e.CapturedBy.Color(),
+			})
+		}
+	}
+	return result
+}
+
+// playTurn plays the route in the game and returns the events of the turn. The
+// events must be converted right away, because the pieces can change later,
+// e.g. when captured and converted.
+func playTurn(game *mess.Game, route mess.Route) ([]TurnEvent, error) {
+	err := game.PlayTurn(route)
+	if err != nil {
+		return nil, err
+	}
+	return turnEvents(game.LastTurn(), nil), nil
+}
+
+// recordEvents converts the events of all the turns recorded in the game.
+// Releasing a captured piece converts it to another player, so the owners are
+// rewound from the last turn back, to describe the pieces as they were at the
+// end of each turn, like playTurn does.
+func recordEvents(record []mess.Turn) [][]TurnEvent {
+	result := make([][]TurnEvent, len(record))
+	owners := make(map[*mess.Piece]*mess.Player)
+	for i := len(record) - 1; i >= 0; i-- {
+		result[i] = turnEvents(record[i], owners)
+		for j := len(record[i]) - 1; j >= 0; j-- {
+			if e, ok := record[i][j].(mess.PieceConverted); ok {
+				owners[e.Piece] = e.From
+			}
+		}
+	}
+	return result
+}
+
+// Turns returns all the turns played so far, in the order they were played.
+func (g *Game) Turns() []Turn {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	players := g.game.Players()
+	nPlayers := len(players)
+	current := slices.Index(players, g.game.CurrentPlayer())
+	first := g.game.TurnNumber() - len(g.routes)
+
+	result := make([]Turn, 0, len(g.routes))
+	for i, route := range g.routes {
+		// the players take their turns in order, ending just before the current one
+		player := players[((current-len(g.routes)+i)%nPlayers+nPlayers)%nPlayers]
+		result = append(result, Turn{
+			Number: first + i,
+			Color:  player.Color(),
+			Player: g.players[player.Color()],
+			Route:  route,
+			Events: g.turnEvents[i],
+		})
+	}
+	return result
+}
+
+// Turn returns the turn of the given number, if it was played.
+func (g *Game) Turn(number int) (Turn, bool) {
+	turns := g.Turns()
+	i := slices.IndexFunc(turns, func(turn Turn) bool { return turn.Number == number })
+	if i == -1 {
+		return Turn{}, false
+	}
+	return turns[i], true
+}
+
+// StateAt returns the state of the game at the start of the turn of the given
+// number, i.e. after all the previous turns were played. The state is rebuilt
+// by replaying the game from its setup.
+func (g *Game) StateAt(turn int) (*State, error) {
+	g.mutex.Lock()
+	rulesFile, setup, routes := g.rules, g.setup, slices.Clone(g.routes)
+	first := g.game.TurnNumber() - len(routes)
+	g.mutex.Unlock()
+
+	if turn < first || turn > first+len(routes) {
+		return nil, ErrNoSuchTurn
+	}
+
+	game, err := replay(rulesFile, setup, first, routes[:turn-first])
+	if err != nil {
+		return nil, err
+	}
+	return g.stateOf(game, game.PieceTypesByName()), nil
+}
+
+// replay rebuilds a game by playing the routes from the setup. The first
+// route is the one played in the turn of the given number.
+func replay(rulesFile *rules.File, setup string, first int, routes []mess.Route) (*mess.Game, error) {
+	game, err := decodeGame(rulesFile, setup)
+	if err != nil {
+		return nil, err
+	}
+	pieceTypes := game.PieceTypesByName()
+	for i, route := range routes {
+		route, err = rebindRoute(route, pieceTypes)
+		if err != nil {
+			return nil, fmt.Errorf("rebinding turn %d: %w", first+i, err)
+		}
+		err = game.PlayTurn(route)
+		if err != nil {
+			return nil, fmt.Errorf("replaying turn %d: %w", first+i, err)
+		}
+	}
+	return game, nil
+}
+
+var ErrNoSuchTurn = usrerr.Errorf("no such turn in the game")
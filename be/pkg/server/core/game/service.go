@@ -1,9 +1,14 @@
 package game
 
 import (
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/jostrzol/mess/pkg/engine"
 	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
 	"github.com/jostrzol/mess/pkg/server/core/event"
 	"github.com/jostrzol/mess/pkg/server/core/id"
 	"github.com/jostrzol/mess/pkg/server/core/usrerr"
@@ -15,6 +20,10 @@ type Service struct {
 	events     *event.Broker `container:"type"`
 	repository Repository    `container:"type"`
 	logger     *zap.Logger   `container:"type"`
+	engine     engine.Engine `container:"type"`
+	// timers schedule the timeout check of each game played with a clock
+	timers      map[id.Game]*time.Timer
+	timersMutex sync.Mutex
 }
 
 func init() {
@@ -37,6 +46,24 @@ func (s *Service) GetGameState(roomID id.Room) (*State, error) {
 	return game.State(), nil
 }
 
+// GetGameStateAt returns the state of the game at the start of the turn of
+// the given number.
+func (s *Service) GetGameStateAt(roomID id.Room, turn int) (*State, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting game %v: %w", roomID, err)
+	}
+	return game.StateAt(turn)
+}
+
+func (s *Service) GetTurns(roomID id.Room) ([]Turn, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting game %v: %w", roomID, err)
+	}
+	return game.Turns(), nil
+}
+
 func (s *Service) GetTurnOptions(roomID id.Room) (*mess.OptionNode, error) {
 	game, err := s.repository.GetForRoom(roomID)
 	if err != nil {
@@ -70,6 +97,170 @@ func (s *Service) PlayTurn(sessionID id.Session, roomID id.Room, turn int, route
 	return game.State(), nil
 }
 
+func (s *Service) GetTakeback(roomID id.Room) (*Takeback, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	return game.Takeback(), nil
+}
+
+func (s *Service) RequestTakeback(sessionID id.Session, roomID id.Room, turns int) (*Takeback, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := game.RequestTakeback(sessionID, turns)
+	if err != nil {
+		return nil, fmt.Errorf("requesting takeback: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.Takeback(), nil
+}
+
+func (s *Service) AnswerTakeback(sessionID id.Session, roomID id.Room, accept bool) (*State, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := game.AnswerTakeback(sessionID, accept)
+	if err != nil {
+		return nil, fmt.Errorf("answering takeback: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.State(), nil
+}
+
+func (s *Service) Resign(sessionID id.Session, roomID id.Room) (*Resolution, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := game.Resign(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("resigning: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.Resolution(), nil
+}
+
+func (s *Service) Abort(sessionID id.Session, roomID id.Room) (*Resolution, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := game.Abort(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("aborting game: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.Resolution(), nil
+}
+
+func (s *Service) GetDrawOffer(roomID id.Room) (*DrawOffer, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	return game.DrawOffer(), nil
+}
+
+func (s *Service) OfferDraw(sessionID id.Session, roomID id.Room) (*DrawOffer, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := game.OfferDraw(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("offering draw: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.DrawOffer(), nil
+}
+
+func (s *Service) AnswerDrawOffer(sessionID id.Session, roomID id.Room, accept bool) (*Resolution, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := game.AnswerDrawOffer(sessionID, accept)
+	if err != nil {
+		return nil, fmt.Errorf("answering draw offer: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.Resolution(), nil
+}
+
+func (s *Service) ExportGame(roomID id.Room) (*notation.GameFile, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	return game.Export(), nil
+}
+
+func (s *Service) ImportGame(sessionID id.Session, roomID id.Room, src []byte) (*State, error) {
+	game, err := s.repository.GetForRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	file, err := notation.ParseGameFile(src)
+	if err != nil {
+		return nil, usrerr.Errorf("parsing game file: %w", err)
+	}
+	ev, err := game.Import(sessionID, file)
+	if err != nil {
+		return nil, fmt.Errorf("importing game: %w", err)
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		return nil, fmt.Errorf("saving game: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return game.State(), nil
+}
+
 func (s *Service) GetResolution(roomID id.Room) (*Resolution, error) {
 	game, err := s.repository.GetForRoom(roomID)
 	if err != nil {
@@ -106,5 +297,147 @@ func (s *Service) Handle(evnt event.Event) {
 			s.logger.Error("saving game", zap.Error(err))
 			return
 		}
+		s.events.Notify(&event.GameCreated{
+			GameID: game.ID(),
+			RoomID: ev.RoomID,
+			By:     ev.By,
+		})
+		s.scheduleTimeoutCheck(game)
+		s.playComputerTurn(game)
+	case *event.GameChanged:
+		game, err := s.repository.Get(ev.GameID)
+		if err != nil {
+			s.logger.Error("getting game", zap.Error(err))
+			return
+		}
+		s.scheduleTimeoutCheck(game)
+		s.playComputerTurn(game)
+	case *event.GameRestored:
+		game, err := s.repository.Get(ev.GameID)
+		if err != nil {
+			s.logger.Error("getting game", zap.Error(err))
+			return
+		}
+		s.scheduleTimeoutCheck(game)
+		s.playComputerTurn(game)
+	case *event.TakebackRequested:
+		game, err := s.repository.Get(ev.GameID)
+		if err != nil {
+			s.logger.Error("getting game", zap.Error(err))
+			return
+		}
+		s.answerComputerTakeback(game)
+	case *event.DrawOffered:
+		game, err := s.repository.Get(ev.GameID)
+		if err != nil {
+			s.logger.Error("getting game", zap.Error(err))
+			return
+		}
+		s.answerComputerDrawOffer(game)
+	}
+}
+
+// scheduleTimeoutCheck checks the game for a timeout as soon as the current
+// player could run out of time. The check scheduled before for the game, if
+// any, is cancelled.
+func (s *Service) scheduleTimeoutCheck(game *Game) {
+	s.timersMutex.Lock()
+	defer func() { s.timersMutex.Unlock() }()
+
+	gameID := game.ID()
+	if timer, ok := s.timers[gameID]; ok {
+		timer.Stop()
+		delete(s.timers, gameID)
+	}
+	timeLeft, ok := game.TimeLeft()
+	if !ok {
+		return
+	}
+	if s.timers == nil {
+		s.timers = make(map[id.Game]*time.Timer)
+	}
+	s.timers[gameID] = time.AfterFunc(timeLeft, func() { s.checkTimeout(gameID) })
+}
+
+func (s *Service) checkTimeout(gameID id.Game) {
+	game, err := s.repository.Get(gameID)
+	if err != nil {
+		s.logger.Error("getting game", zap.Error(err))
+		return
+	}
+	// either checks again later or, after a timeout, drops the timer
+	defer s.scheduleTimeoutCheck(game)
+
+	ev := game.CheckTimeout()
+	if ev == nil {
+		return
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		s.logger.Error("saving game", zap.Error(err))
+		return
+	}
+	s.events.Notify(ev)
+}
+
+// playComputerTurn plays the computer's turn in the background, so that the
+// event which triggered it is handled without waiting for the search.
+func (s *Service) playComputerTurn(game *Game) {
+	if !game.IsComputerTurn() || game.Resolution().IsResolved {
+		return
+	}
+	go func() {
+		ev, err := game.PlayComputerTurn(s.engine)
+		if errors.Is(err, ErrComputerTurnOutdated) {
+			// the change is handled again on its own event
+			return
+		} else if err != nil {
+			s.logger.Error("playing computer turn", zap.Error(err))
+			return
+		}
+		err = s.repository.Save(game)
+		if err != nil {
+			s.logger.Error("saving game", zap.Error(err))
+			return
+		}
+		s.events.Notify(ev)
+	}()
+}
+
+// answerComputerTakeback accepts all the takebacks requested from the computer.
+func (s *Service) answerComputerTakeback(game *Game) {
+	takeback := game.Takeback()
+	if game.Computer().IsZero() || takeback == nil || takeback.By == game.Computer() {
+		return
 	}
+	ev, err := game.AnswerTakeback(game.Computer(), true)
+	if err != nil {
+		s.logger.Error("answering takeback", zap.Error(err))
+		return
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		s.logger.Error("saving game", zap.Error(err))
+		return
+	}
+	s.events.Notify(ev)
+}
+
+// answerComputerDrawOffer declines all the draws offered to the computer.
+func (s *Service) answerComputerDrawOffer(game *Game) {
+	drawOffer := game.DrawOffer()
+	if game.Computer().IsZero() || drawOffer == nil || drawOffer.By == game.Computer() {
+		return
+	}
+	ev, err := game.AnswerDrawOffer(game.Computer(), false)
+	if err != nil {
+		s.logger.Error("answering draw offer", zap.Error(err))
+		return
+	}
+	err = s.repository.Save(game)
+	if err != nil {
+		s.logger.Error("saving game", zap.Error(err))
+		return
+	}
+	s.events.Notify(ev)
 }
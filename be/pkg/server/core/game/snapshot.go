@@ -0,0 +1,132 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Snapshot contains everything needed to rebuild a game, e.g. after loading
+// it from a persistent storage.
+type Snapshot struct {
+	ID          id.Game
+	RoomID      id.Room
+	Players     map[color.Color]id.Session
+	Computer    id.Session
+	Rules       *rules.File
+	Setup       string
+	Routes      []mess.Route
+	Takeback    *Takeback
+	TimeControl clock.TimeControl
+	Clocks      map[color.Color]clock.Clock
+	TurnStart   time.Time
+	TimedOut    id.Session
+	DrawOffer   *DrawOffer
+	Ending      *Resolution
+}
+
+func (g *Game) Snapshot() *Snapshot {
+	g.mutex.Lock()
+	defer func() { g.mutex.Unlock() }()
+
+	var takeback *Takeback
+	if g.takeback != nil {
+		takebackCopy := *g.takeback
+		takebackCopy.AcceptedBy = slices.Clone(g.takeback.AcceptedBy)
+		takeback = &takebackCopy
+	}
+	var drawOffer *DrawOffer
+	if g.drawOffer != nil {
+		drawOfferCopy := *g.drawOffer
+		drawOfferCopy.AcceptedBy = slices.Clone(g.drawOffer.AcceptedBy)
+		drawOffer = &drawOfferCopy
+	}
+	var ending *Resolution
+	if g.ending != nil {
+		endingCopy := *g.ending
+		ending = &endingCopy
+	}
+
+	return &Snapshot{
+		ID:          g.id,
+		RoomID:      g.room,
+		Players:     maps.Clone(g.players),
+		Computer:    g.computer,
+		Rules:       g.rules,
+		Setup:       g.setup,
+		Routes:      append([]mess.Route(nil), g.routes...),
+		Takeback:    takeback,
+		TimeControl: g.timeControl,
+		Clocks:      maps.Clone(g.clocks),
+		TurnStart:   g.turnStart,
+		TimedOut:    g.timedOut,
+		DrawOffer:   drawOffer,
+		Ending:      ending,
+	}
+}
+
+// Restore rebuilds the game from the snapshot by replaying all of its routes
+// from its setup.
+// Piece types in the routes are matched with the rebuilt game's ones by name.
+func Restore(snapshot *Snapshot) (*Game, error) {
+	g, err := newGame(
+		snapshot.ID,
+		snapshot.RoomID,
+		maps.Clone(snapshot.Players),
+		snapshot.Computer,
+		snapshot.Rules,
+		snapshot.Setup,
+		snapshot.TimeControl,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, route := range snapshot.Routes {
+		route, err = rebindRoute(route, g.cachedPieceTypes)
+		if err != nil {
+			return nil, fmt.Errorf("restoring turn %d: %w", i, err)
+		}
+		events, err := playTurn(g.game, route)
+		if err != nil {
+			return nil, fmt.Errorf("replaying turn %d: %w", i, err)
+		}
+		g.routes = append(g.routes, route)
+		g.turnEvents = append(g.turnEvents, events)
+	}
+	g.takeback = snapshot.Takeback
+	if snapshot.Clocks != nil {
+		g.clocks = maps.Clone(snapshot.Clocks)
+	}
+	if !snapshot.TurnStart.IsZero() {
+		g.turnStart = snapshot.TurnStart
+	}
+	g.timedOut = snapshot.TimedOut
+	g.drawOffer = snapshot.DrawOffer
+	g.ending = snapshot.Ending
+
+	g.calculateState()
+	return g, nil
+}
+
+func rebindRoute(route mess.Route, pieceTypes map[string]*mess.PieceType) (mess.Route, error) {
+	result := make(mess.Route, 0, len(route))
+	for _, option := range route {
+		if opt, ok := option.(mess.PieceTypeOption); ok {
+			pieceType, ok := pieceTypes[opt.PieceType.Name()]
+			if !ok {
+				return nil, fmt.Errorf("piece type %q not found", opt.PieceType.Name())
+			}
+			option = mess.PieceTypeOption{PieceType: pieceType}
+		}
+		result = append(result, option)
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package room
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jostrzol/mess/pkg/server/core/event"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+	"github.com/jostrzol/mess/pkg/server/core/usrerr"
+)
+
+const (
+	// MaxChatMessageLength is the maximum number of characters in a message.
+	MaxChatMessageLength = 500
+	// MaxChatHistory is the number of the latest messages kept in a room.
+	MaxChatHistory = 200
+	// ChatRateLimit is the number of messages a member can send within
+	// ChatRateWindow.
+	ChatRateLimit  = 5
+	ChatRateWindow = 10 * time.Second
+)
+
+type ChatMessage struct {
+	Author id.Session
+	Text   string
+	Time   time.Time
+}
+
+// Chat sends the message to all the members of the room and stores it in the
+// chat history.
+func (r *Room) Chat(session id.Session, text string) (*ChatMessage, event.Event, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+
+	now := time.Now()
+	text = strings.TrimSpace(text)
+	switch {
+	case !r.isMember(session):
+		return nil, nil, ErrNotInRoom
+	case text == "":
+		return nil, nil, ErrEmptyMessage
+	case utf8.RuneCountInString(text) > MaxChatMessageLength:
+		return nil, nil, ErrMessageTooLong
+	case r.recentMessages(session, now) >= ChatRateLimit:
+		return nil, nil, ErrChatRateLimit
+	}
+
+	message := ChatMessage{Author: session, Text: text, Time: now}
+	r.chat = append(r.chat, message)
+	if len(r.chat) > MaxChatHistory {
+		r.chat = slices.Clone(r.chat[len(r.chat)-MaxChatHistory:])
+	}
+	return &message, &event.ChatMessageSent{
+		RoomID: r.id,
+		By:     session,
+		Text:   text,
+		Time:   now,
+	}, nil
+}
+
+// ChatHistory returns the latest messages of the room's chat, oldest first.
+// Only the members of the room can read it.
+func (r *Room) ChatHistory(session id.Session) ([]ChatMessage, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+
+	if !r.isMember(session) {
+		return nil, ErrNotInRoom
+	}
+	return slices.Clone(r.chat), nil
+}
+
+func (r *Room) isMember(session id.Session) bool {
+	return slices.Contains(r.players, session) || slices.Contains(r.spectators, session)
+}
+
+// recentMessages counts the messages sent by the session within the rate
+// limit window.
+func (r *Room) recentMessages(session id.Session, now time.Time) int {
+	count := 0
+	for i := len(r.chat) - 1; i >= 0 && now.Sub(r.chat[i].Time) < ChatRateWindow; i-- {
+		if r.chat[i].Author == session {
+			count++
+		}
+	}
+	return count
+}
+
+var ErrEmptyMessage = usrerr.Errorf("chat message is empty")
+var ErrMessageTooLong = usrerr.Errorf("chat message is longer than %d characters", MaxChatMessageLength)
+var ErrChatRateLimit = usrerr.Errorf("too many chat messages, try again in a moment")
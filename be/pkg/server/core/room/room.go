@@ -6,28 +6,57 @@ import (
 	"slices"
 	"sync"
 
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/mess"
 	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
 	"github.com/jostrzol/mess/pkg/server/core/event"
 	"github.com/jostrzol/mess/pkg/server/core/id"
 	"github.com/jostrzol/mess/pkg/server/core/usrerr"
 )
 
-const PlayersNeeded = 2
-
 type Room struct {
-	id        id.Room
-	players   [2]id.Session
-	nPlayers  int
-	RulesFile *rules.File
-	game      id.Game
-	mutex     sync.Mutex
+	id      id.Room
+	players []id.Session
+	// colors contains the colors of the players defined by the rules, in
+	// their turn order. The n-th player to join the room plays the n-th color.
+	colors []color.Color
+	// spectators can watch the game, but not take part in it.
+	spectators  []id.Session
+	RulesFile   *rules.File
+	timeControl clock.TimeControl
+	// setup is the position the game starts from, in the setup notation.
+	// Empty if the game starts from the initial state of the rules.
+	setup    string
+	game     id.Game
+	computer id.Session
+	// chat contains the latest messages sent to the room, oldest first.
+	chat  []ChatMessage
+	mutex sync.Mutex
 }
 
 func New() *Room {
+	rulesFile := defaultRulesFile()
 	return &Room{
 		id:        id.New[id.Room](),
-		RulesFile: defaultRulesFile(),
+		colors:    playerColors(rulesFile),
+		RulesFile: rulesFile,
+	}
+}
+
+// playerColors returns the colors of the players defined by the rules. Rules
+// which can't be decoded are only reported when the game starts, so until
+// then the room seats the default players.
+func playerColors(rulesFile *rules.File) []color.Color {
+	colors, err := rules.DecodePlayers(rulesFile)
+	if err == nil {
+		return colors
+	}
+	colors = make([]color.Color, 0, len(mess.DefaultPlayerConfigs))
+	for _, config := range mess.DefaultPlayerConfigs {
+		colors = append(colors, config.Color)
 	}
+	return colors
 }
 
 func defaultRulesFile() *rules.File {
@@ -49,22 +78,92 @@ func (r *Room) ID() id.Room {
 func (r *Room) AddPlayer(sessionID id.Session) (event.Event, error) {
 	r.mutex.Lock()
 	defer func() { r.mutex.Unlock() }()
-	if slices.Contains(r.players[:r.nPlayers], sessionID) {
+	if slices.Contains(r.players, sessionID) {
 		return nil, ErrAlreadyInRoom
 	}
-	if r.nPlayers >= PlayersNeeded {
+	if slices.Contains(r.spectators, sessionID) {
+		return nil, ErrAlreadySpectating
+	}
+	if len(r.players) >= r.PlayersNeeded() {
 		return nil, ErrRoomFull
 	}
-	r.players[r.nPlayers] = sessionID
-	r.nPlayers++
+	r.players = append(r.players, sessionID)
 	return &event.PlayerJoined{
 		RoomID:   r.id,
 		PlayerID: sessionID,
 	}, nil
 }
 
+// AddSpectator lets the session watch the room without taking part in the
+// game. There is no limit on the number of spectators.
+func (r *Room) AddSpectator(sessionID id.Session) (event.Event, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	if slices.Contains(r.players, sessionID) {
+		return nil, ErrAlreadyInRoom
+	}
+	if slices.Contains(r.spectators, sessionID) {
+		return nil, ErrAlreadySpectating
+	}
+	r.spectators = append(r.spectators, sessionID)
+	return &event.SpectatorJoined{
+		RoomID:      r.id,
+		SpectatorID: sessionID,
+	}, nil
+}
+
+// AddComputer adds a player controlled by the computer to the room.
+func (r *Room) AddComputer(sessionID id.Session) (event.Event, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	switch {
+	case slices.Contains(r.spectators, sessionID):
+		return nil, ErrSpectator
+	case !slices.Contains(r.players, sessionID):
+		return nil, ErrNotInRoom
+	case !r.computer.IsZero():
+		return nil, ErrComputerPresent
+	case r.IsStarted():
+		return nil, ErrAlreadyStarted
+	case len(r.players) >= r.PlayersNeeded():
+		return nil, ErrRoomFull
+	}
+	r.computer = id.New[id.Session]()
+	r.players = append(r.players, r.computer)
+	return &event.PlayerJoined{
+		RoomID:   r.id,
+		PlayerID: r.computer,
+	}, nil
+}
+
+// Computer returns the session of the player controlled by the computer,
+// or a zero session if there is none.
+func (r *Room) Computer() id.Session {
+	return r.computer
+}
+
 func (r *Room) Players() []id.Session {
-	return r.players[:r.nPlayers]
+	return r.players
+}
+
+func (r *Room) Spectators() []id.Session {
+	return r.spectators
+}
+
+// Members returns both the players and the spectators of the room.
+func (r *Room) Members() []id.Session {
+	members := make([]id.Session, 0, len(r.players)+len(r.spectators))
+	members = append(members, r.players...)
+	return append(members, r.spectators...)
+}
+
+func (r *Room) IsSpectator(sessionID id.Session) bool {
+	return slices.Contains(r.spectators, sessionID)
+}
+
+// PlayersNeeded returns the number of players defined by the current rules.
+func (r *Room) PlayersNeeded() int {
+	return len(r.colors)
 }
 
 func (r *Room) IsStarted() bool {
@@ -77,8 +176,10 @@ func (r *Room) IsStartable() bool {
 
 func (r *Room) assertStartable() error {
 	switch {
-	case r.nPlayers != PlayersNeeded:
+	case len(r.players) < r.PlayersNeeded():
 		return ErrNotEnoughPlayers
+	case len(r.players) > r.PlayersNeeded():
+		return ErrTooManyPlayers
 	case r.IsStarted():
 		return ErrAlreadyStarted
 	default:
@@ -90,29 +191,110 @@ func (r *Room) Rules() *rules.File {
 	return r.RulesFile
 }
 
+// UpdateRules replaces the rules of the game, which is going to be started in
+// the room. The rules must seat at least as many players as already joined.
 func (r *Room) UpdateRules(session id.Session, filename string, data []byte) (event.Event, error) {
-	if filename == "" {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	switch {
+	case slices.Contains(r.spectators, session):
+		return nil, ErrSpectator
+	case r.IsStarted():
+		return nil, ErrAlreadyStarted
+	case filename == "":
 		return nil, usrerr.Errorf("filename cannot be empty")
 	}
 
-	r.RulesFile = &rules.File{Filename: filename, Src: data}
+	rulesFile := &rules.File{Filename: filename, Src: data}
+	colors := playerColors(rulesFile)
+	if len(colors) < len(r.players) {
+		return nil, ErrTooManyPlayers
+	}
+
+	r.RulesFile = rulesFile
+	r.colors = colors
+	// the setup is written in terms of the previous rules
+	r.setup = ""
 
 	return &event.RoomRulesChanged{RoomID: r.id, By: session}, nil
 }
 
+func (r *Room) TimeControl() clock.TimeControl {
+	return r.timeControl
+}
+
+// SetTimeControl sets the time control of the game, which is going to
+// be started in the room.
+func (r *Room) SetTimeControl(session id.Session, timeControl clock.TimeControl) (event.Event, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	switch {
+	case slices.Contains(r.spectators, session):
+		return nil, ErrSpectator
+	case !slices.Contains(r.players, session):
+		return nil, ErrNotInRoom
+	case r.IsStarted():
+		return nil, ErrAlreadyStarted
+	}
+	if err := timeControl.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.timeControl = timeControl
+	return &event.RoomTimeControlChanged{RoomID: r.id, By: session}, nil
+}
+
+func (r *Room) Setup() string {
+	return r.setup
+}
+
+// SetSetup sets the position the game, which is going to be started in the
+// room, starts from. An empty setup starts the game from the initial state of
+// the rules.
+func (r *Room) SetSetup(session id.Session, setup string) (event.Event, error) {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+	switch {
+	case slices.Contains(r.spectators, session):
+		return nil, ErrSpectator
+	case !slices.Contains(r.players, session):
+		return nil, ErrNotInRoom
+	case r.IsStarted():
+		return nil, ErrAlreadyStarted
+	}
+	if setup != "" {
+		if _, err := rules.DecodeRulesWithSetup(r.RulesFile, setup); err != nil {
+			return nil, usrerr.Errorf("invalid setup: %w", err)
+		}
+	}
+
+	r.setup = setup
+	return &event.RoomSetupChanged{RoomID: r.id, By: session}, nil
+}
+
 func (r *Room) StartGame(sessionID id.Session) (event.Event, error) {
 	r.mutex.Lock()
 	defer func() { r.mutex.Unlock() }()
+	if slices.Contains(r.spectators, sessionID) {
+		return nil, ErrSpectator
+	}
 	if err := r.assertStartable(); err != nil {
 		return nil, err
 	}
 	r.game = id.New[id.Game]()
+	players := make(map[color.Color]id.Session, len(r.players))
+	for i, player := range r.players {
+		players[r.colors[i]] = player
+	}
 	return &event.GameStarted{
-		GameID:  r.game,
-		RoomID:  r.id,
-		Players: r.players,
-		Rules:   r.RulesFile,
-		By:      sessionID,
+		GameID:      r.game,
+		RoomID:      r.id,
+		Players:     players,
+		Rules:       r.RulesFile,
+		TimeControl: r.timeControl,
+		Setup:       r.setup,
+		Computer:    r.computer,
+		By:          sessionID,
 	}, nil
 }
 
@@ -123,5 +305,10 @@ func (r *Room) Game() id.Game {
 var ErrRoomFull = usrerr.Errorf("room full")
 var ErrNoRules = usrerr.Errorf("no rules file")
 var ErrNotEnoughPlayers = usrerr.Errorf("not enough players")
+var ErrTooManyPlayers = usrerr.Errorf("too many players for the current rules")
 var ErrAlreadyStarted = usrerr.Errorf("game is already started")
 var ErrAlreadyInRoom = usrerr.Errorf("player already in room")
+var ErrNotInRoom = usrerr.Errorf("player not in room")
+var ErrAlreadySpectating = usrerr.Errorf("player already spectating the room")
+var ErrSpectator = usrerr.Errorf("spectators cannot modify the room")
+var ErrComputerPresent = usrerr.Errorf("room already has a computer player")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
 	"github.com/jostrzol/mess/pkg/server/core/event"
 	"github.com/jostrzol/mess/pkg/server/core/id"
 	"github.com/jostrzol/mess/pkg/server/ioc"
@@ -49,6 +50,40 @@ func (s *Service) JoinRoom(sessionID id.Session, roomID id.Room) (*Room, error)
 	return room, nil
 }
 
+func (s *Service) SpectateRoom(sessionID id.Session, roomID id.Room) (*Room, error) {
+	room, err := s.repository.Get(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+	ev, err := room.AddSpectator(sessionID)
+	if err != nil {
+		return room, fmt.Errorf("adding a spectator: %w", err)
+	}
+	err = s.repository.Save(room)
+	if err != nil {
+		return room, fmt.Errorf("saving room: %w", err)
+	}
+	s.events.Notify(ev)
+	return room, nil
+}
+
+func (s *Service) AddComputer(sessionID id.Session, roomID id.Room) (*Room, error) {
+	room, err := s.repository.Get(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+	ev, err := room.AddComputer(sessionID)
+	if err != nil {
+		return room, fmt.Errorf("adding a computer player: %w", err)
+	}
+	err = s.repository.Save(room)
+	if err != nil {
+		return room, fmt.Errorf("saving room: %w", err)
+	}
+	s.events.Notify(ev)
+	return room, nil
+}
+
 func (s *Service) GetRoom(roomID id.Room) (*Room, error) {
 	room, err := s.repository.Get(roomID)
 	if err != nil {
@@ -75,11 +110,84 @@ func (s *Service) SetRules(session id.Session, roomID id.Room, filename string,
 	if err != nil {
 		return fmt.Errorf("setting rules: %w", err)
 	}
+	err = s.repository.Save(room)
+	if err != nil {
+		return fmt.Errorf("saving room: %w", err)
+	}
 	s.events.Notify(ev)
 
 	return nil
 }
 
+func (s *Service) SetSetup(session id.Session, roomID id.Room, setup string) (*Room, error) {
+	room, err := s.repository.Get(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := room.SetSetup(session, setup)
+	if err != nil {
+		return nil, fmt.Errorf("setting setup: %w", err)
+	}
+	err = s.repository.Save(room)
+	if err != nil {
+		return nil, fmt.Errorf("saving room: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return room, nil
+}
+
+func (s *Service) SetTimeControl(session id.Session, roomID id.Room, timeControl clock.TimeControl) (*Room, error) {
+	room, err := s.repository.Get(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	ev, err := room.SetTimeControl(session, timeControl)
+	if err != nil {
+		return nil, fmt.Errorf("setting time control: %w", err)
+	}
+	err = s.repository.Save(room)
+	if err != nil {
+		return nil, fmt.Errorf("saving room: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return room, nil
+}
+
+func (s *Service) GetChat(session id.Session, roomID id.Room) (*Room, []ChatMessage, error) {
+	room, err := s.repository.Get(roomID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+	messages, err := room.ChatHistory(session)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting chat history: %w", err)
+	}
+	return room, messages, nil
+}
+
+func (s *Service) Chat(session id.Session, roomID id.Room, text string) (*Room, *ChatMessage, error) {
+	room, err := s.repository.Get(roomID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting room %v: %w", roomID, err)
+	}
+
+	message, ev, err := room.Chat(session, text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sending chat message: %w", err)
+	}
+	err = s.repository.Save(room)
+	if err != nil {
+		return nil, nil, fmt.Errorf("saving room: %w", err)
+	}
+	s.events.Notify(ev)
+
+	return room, message, nil
+}
+
 func (s *Service) StartGame(sessionID id.Session, roomID id.Room) (*Room, error) {
 	room, err := s.repository.Get(roomID)
 	if err != nil {
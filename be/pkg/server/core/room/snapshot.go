@@ -0,0 +1,54 @@
+package room
+
+import (
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/jostrzol/mess/pkg/server/core/clock"
+	"github.com/jostrzol/mess/pkg/server/core/id"
+)
+
+// Snapshot contains everything needed to rebuild a room, e.g. after loading
+// it from a persistent storage.
+type Snapshot struct {
+	ID          id.Room
+	Players     []id.Session
+	Spectators  []id.Session
+	RulesFile   *rules.File
+	TimeControl clock.TimeControl
+	Setup       string
+	Game        id.Game
+	Computer    id.Session
+	Chat        []ChatMessage
+}
+
+func (r *Room) Snapshot() *Snapshot {
+	r.mutex.Lock()
+	defer func() { r.mutex.Unlock() }()
+
+	return &Snapshot{
+		ID:          r.id,
+		Players:     append([]id.Session(nil), r.players...),
+		Spectators:  append([]id.Session(nil), r.spectators...),
+		RulesFile:   r.RulesFile,
+		TimeControl: r.timeControl,
+		Setup:       r.setup,
+		Game:        r.game,
+		Computer:    r.computer,
+		Chat:        append([]ChatMessage(nil), r.chat...),
+	}
+}
+
+func Restore(snapshot *Snapshot) (*Room, error) {
+	result := &Room{
+		id:          snapshot.ID,
+		players:     append([]id.Session(nil), snapshot.Players...),
+		spectators:  append([]id.Session(nil), snapshot.Spectators...),
+		colors:      playerColors(snapshot.RulesFile),
+		RulesFile:   snapshot.RulesFile,
+		timeControl: snapshot.TimeControl,
+		setup:       snapshot.Setup,
+		game:        snapshot.Game,
+		computer:    snapshot.Computer,
+		chat:        append([]ChatMessage(nil), snapshot.Chat...),
+	}
+	return result, nil
+}
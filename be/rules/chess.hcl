@@ -1,10 +1,31 @@
 // ===== BOARD ================================================================
-// Board size definition.
+// Board size definition. Non-rectangular boards can be made by disabling some
+// of the squares, either by listing them in the attribute "disabled_squares"
+// or by marking them with '#' in the attribute "mask" (one string per rank,
+// starting from the top one, with '.' for the regular squares).
+//
+// The attribute "topology" changes how the offsets of the motions are resolved:
+// "rectangular" (the default), "cylindrical" (files wrap around), "toroidal"
+// (files and ranks wrap around) or "hexagonal" (axial coordinates, see
+// board.directions for the neighbour offsets).
 board {
   height = 8
   width  = 8
 }
 
+// ===== PLAYERS ==============================================================
+// Players block lists the players in their turn order. Each player has
+// a color and a forward direction, which is the direction their pieces are
+// heading to. If omitted, white and black players are assumed.
+players {
+  player "white" {
+    forward_direction = [0, 1]
+  }
+  player "black" {
+    forward_direction = [0, -1]
+  }
+}
+
 // ===== PIECE TYPES SPECIFICATION ============================================
 // Each piece type should specify the motions it is able to perform.
 //
@@ -36,17 +57,20 @@ board {
 // definition.
 //
 // Piece appearance can be also configured via the "presentation" block inside
-// the piece type's definition.
+// the piece type's definition. The "code" attribute sets the letter denoting
+// the piece in the setup notation of positions, and defaults to the symbol.
 
 piece_types {
   piece_type "king" {
     presentation {
       white {
         symbol = "♔"
+        code   = "K"
         icon   = "/piece_types/king.svg"
       }
       black {
         symbol = "♚"
+        code   = "k"
         icon   = "/piece_types/king.svg"
       }
     }
@@ -63,10 +87,12 @@ piece_types {
     presentation {
       white {
         symbol = "♕"
+        code   = "Q"
         icon   = "/piece_types/queen.svg"
       }
       black {
         symbol = "♛"
+        code   = "q"
         icon   = "/piece_types/queen.svg"
       }
     }
@@ -82,10 +108,12 @@ piece_types {
     presentation {
       white {
         symbol = "♖"
+        code   = "R"
         icon   = "/piece_types/rook.svg"
       }
       black {
         symbol = "♜"
+        code   = "r"
         icon   = "/piece_types/rook.svg"
       }
     }
@@ -98,10 +126,12 @@ piece_types {
     presentation {
       white {
         symbol = "♘"
+        code   = "N"
         icon   = "/piece_types/knight.svg"
       }
       black {
         symbol = "♞"
+        code   = "n"
         icon   = "/piece_types/knight.svg"
       }
     }
@@ -114,10 +144,12 @@ piece_types {
     presentation {
       white {
         symbol = "♗"
+        code   = "B"
         icon   = "/piece_types/bishop.svg"
       }
       black {
         symbol = "♝"
+        code   = "b"
         icon   = "/piece_types/bishop.svg"
       }
     }
@@ -130,10 +162,12 @@ piece_types {
     presentation {
       white {
         symbol = "♙"
+        code   = "P"
         icon   = "/piece_types/pawn.svg"
       }
       black {
         symbol = "♟"
+        code   = "p"
         icon   = "/piece_types/pawn.svg"
       }
     }
@@ -556,15 +590,16 @@ composite_function "turn" {
 
 // This function is called at the end of every turn.
 // Returns an object of type {did_end: bool, winner: color}. If did_end == true
-// and winner == null then draw is concluded.
+// and winner == null then draw is concluded. The object can optionally contain
+// the reason of the game end, e.g. "checkmate" - "rule_defined" by default.
 composite_function "resolve" {
   params = [game]
   result = {
     losing_player = check_mated_player(game)
     return = (
       losing_player == null
-      ? { did_end = false, winner = null }
-      : { did_end = true, winner = opponent(losing_player).color }
+      ? { did_end = false, winner = null, reason = null }
+      : { did_end = true, winner = opponent(losing_player).color, reason = "checkmate" }
     )
   }
 }
@@ -589,6 +624,57 @@ composite_function "check_mated_player" {
   }
 }
 
+// ===== DRAWS ================================================================
+// The optional "draws" block declares when the game ends in a draw
+// automatically:
+//   * repetitions - when the same position occurs the given number of times,
+//   * plies_without_progress - when the given number of turns is played
+//     without any capture or a move of any of the progress_piece_types.
+// A position consists of the pieces on the board and the current player.
+// The state not visible on the board can be added by the function named in
+// the "position_extra" attribute, receiving the game and returning any value.
+draws {
+  repetitions            = 3
+  plies_without_progress = 100
+  progress_piece_types   = ["pawn"]
+  position_extra         = "castling_and_en_passant_rights"
+}
+
+// Returns the squares of the kings and rooks able to castle and the square of
+// the pawn, which can be captured en passant (if any).
+composite_function "castling_and_en_passant_rights" {
+  params = [game]
+  result = {
+    pieces    = concat([for player in game.players : player.pieces]...)
+    castlers  = [for piece in pieces : piece if contains(["king", "rook"], piece.type)]
+    castling  = [for piece in castlers : piece.square if !has_ever_moved(piece)]
+    last_move = last_or_null(game.record)
+    is_double = last_move == null ? false : (
+      last_move.piece.type == "pawn"
+      && abs(square_to_coords(last_move.dst)[1] - square_to_coords(last_move.src)[1]) == 2
+    )
+    return = {
+      castling   = sort(castling)
+      en_passant = is_double ? last_move.dst : null
+    }
+  }
+}
+
+// ===== EVALUATION ===========================================================
+// The function "evaluate" is optional. It is used by the computer player to
+// estimate how favourable the game state is for the given player - the greater
+// the result, the better. If it is not defined, the difference in material and
+// mobility is used instead.
+composite_function "evaluate" {
+  params = [game, player]
+  result = {
+    values   = { pawn = 1, knight = 3, bishop = 3, rook = 5, queen = 9, king = 0 }
+    own      = sum(0, [for piece in player.pieces : values[piece.type]]...)
+    opponent = sum(0, [for piece in opponent(player).pieces : values[piece.type]]...)
+    return   = own - opponent
+  }
+}
+
 // ===== ASSETS ===============================================================
 // Assets are additional files, which can be used in rules. These files are
 // copressed via gzip, and then ascii-encoded via base64. Any whitespace is
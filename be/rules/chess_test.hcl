@@ -0,0 +1,99 @@
+// Tests of the chess rules. Run them with:
+//   go run ./cmd/mess test --rules ./rules/chess.hcl
+// Read the documentation of the package pkg/rules/rulestest to learn more.
+
+test "castling" {
+  position = {
+    white = { E1 = "king", A1 = "rook", H1 = "rook" }
+    black = { E8 = "king" }
+  }
+  expect_moves = {
+    E1 = ["C1", "D1", "D2", "E2", "F1", "F2", "G1"]
+  }
+}
+
+test "castling_moves_rook" {
+  position = {
+    white = { E1 = "king", A1 = "rook", H1 = "rook" }
+    black = { E8 = "king" }
+  }
+  play = ["E1-G1"]
+  expect_pieces = {
+    white = { G1 = "king", F1 = "rook" }
+  }
+  expect_empty = ["E1", "H1"]
+}
+
+test "castling_blocked_after_king_moved" {
+  position = {
+    white = { E1 = "king", A1 = "rook", H1 = "rook" }
+    black = { E8 = "king" }
+  }
+  play = ["E1-E2", "E8-E7", "E2-E1", "E7-E8"]
+  expect_moves = {
+    E1 = ["D1", "D2", "E2", "F1", "F2"]
+  }
+}
+
+test "castling_blocked_by_enemy_on_path" {
+  position = {
+    white = { E1 = "king", A1 = "rook", H1 = "rook" }
+    black = { E8 = "king", D3 = "rook", F3 = "rook" }
+  }
+  expect_moves = {
+    E1 = ["E2"]
+  }
+}
+
+test "en_passant" {
+  position = {
+    white = { E1 = "king", E5 = "pawn" }
+    black = { E8 = "king", D7 = "pawn" }
+  }
+  turn = "black"
+  play = ["D7-D5"]
+  expect_moves = {
+    E5 = ["D6", "E6"]
+  }
+}
+
+test "en_passant_captures_pawn" {
+  position = {
+    white = { E1 = "king", E5 = "pawn" }
+    black = { E8 = "king", D7 = "pawn" }
+  }
+  turn = "black"
+  play = ["D7-D5", "E5-D6"]
+  expect_pieces = {
+    white = { D6 = "pawn" }
+  }
+  expect_empty = ["D5", "E5"]
+}
+
+test "promotion" {
+  position = {
+    white = { E1 = "king", A7 = "pawn" }
+    black = { H8 = "king" }
+  }
+  play = ["A7-A8/queen"]
+  expect_pieces = {
+    white = { A8 = "queen" }
+  }
+}
+
+test "fools_mate" {
+  play = ["F2-F3", "E7-E5", "G2-G4", "D8-H4"]
+  expect_resolution {
+    did_end = true
+    winner  = "black"
+    reason  = "checkmate"
+  }
+}
+
+test "stalemate" {
+  setup = "7k/5K2/6Q1/8/8/8/8/8 black - 0"
+  expect_resolution {
+    did_end = true
+    reason  = "stalemate"
+  }
+}
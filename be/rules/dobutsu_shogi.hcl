@@ -5,6 +5,18 @@ board {
   width  = 3
 }
 
+// ===== ZONES ================================================================
+// Zones name sets of squares. The squares listed in the attribute "squares"
+// belong to the zone for every player, while the ones listed in an attribute
+// named after a player's color belong to the zone only for that player.
+// Zones can be queried with the functions "in_zone" and "zone_squares".
+zones {
+  zone "last_rank" {
+    white = ["A4", "B4", "C4"]
+    black = ["A1", "B1", "C1"]
+  }
+}
+
 // ===== PIECE TYPES SPECIFICATION ============================================
 // Each piece type should specify the motions it is able to perform.
 //
@@ -206,11 +218,8 @@ composite_function "motion_forward_straight" {
 composite_function "promote" {
   params = [piece, src, dst, options]
   result = {
-    owner     = owner_of(piece)
-    forward_y = owner.forward_direction[1]
-    last_y    = forward_y == 1 ? board.height - 1 : 0
-    pos       = square_to_coords(dst)
-    return    = cond_call(pos[1] == last_y, "place_new_piece", "hen", dst, owner.color)
+    owner  = owner_of(piece)
+    return = cond_call(in_zone(dst, "last_rank", owner.color), "place_new_piece", "hen", dst, owner.color)
   }
 }
 
@@ -359,18 +368,7 @@ composite_function "has_won" {
   params = [player]
   result = {
     lions  = [for piece in player.pieces : piece if piece.type == "lion"]
-    return = any([for lion in lions : is_in_final_rank(player.forward_direction, lion)]...)
-  }
-}
-
-// Checks if the given piece is in the final rank.
-composite_function "is_in_final_rank" {
-  params = [forward_direction, piece]
-  result = {
-    forward_y = forward_direction[1]
-    last_y    = forward_y == 1 ? board.height - 1 : 0
-    pos       = square_to_coords(piece.square)
-    return    = pos[1] == last_y
+    return = any([for lion in lions : in_zone(lion.square, "last_rank", player.color)]...)
   }
 }
 
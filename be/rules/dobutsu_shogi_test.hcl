@@ -0,0 +1,26 @@
+// Tests of the dobutsu shogi rules. Run them with:
+//   go run ./cmd/mess test --rules ./rules/dobutsu_shogi.hcl
+// Read the documentation of the package pkg/rules/rulestest to learn more.
+
+test "chick_promotes_to_hen" {
+  position = {
+    white = { B1 = "lion", A3 = "chick" }
+    black = { C4 = "lion" }
+  }
+  play = ["*/A3-A4"]
+  expect_pieces = {
+    white = { A4 = "hen" }
+  }
+}
+
+test "lion_try" {
+  position = {
+    white = { A3 = "lion" }
+    black = { C2 = "lion" }
+  }
+  play = ["*/A3-A4"]
+  expect_resolution {
+    did_end = true
+    winner  = "white"
+  }
+}
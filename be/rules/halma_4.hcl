@@ -0,0 +1,317 @@
+// ===== CONSTANTS ============================================================
+// Constants contain arbitrary data, which can be accessed in other blocks.
+constants {
+  // Each player starts in a corner camp of 13 pieces.
+  camps = {
+    white = [
+      "A4", "B4",
+      "A3", "B3", "C3",
+      "A2", "B2", "C2", "D2",
+      "A1", "B1", "C1", "D1",
+    ]
+    red = [
+      "A16", "B16", "C16", "D16",
+      "A15", "B15", "C15", "D15",
+      "A14", "B14", "C14",
+      "A13", "B13",
+    ]
+    black = [
+      "M16", "N16", "O16", "P16",
+      "M15", "N15", "O15", "P15",
+      /*  */ "N14", "O14", "P14",
+      /*         */ "O13", "P13",
+    ]
+    blue = [
+      /*         */ "O4", "P4",
+      /*  */ "N3", "O3", "P3",
+      "M2", "N2", "O2", "P2",
+      "M1", "N1", "O1", "P1",
+    ]
+  }
+  // The goal of each player is to move all the pieces into the camp
+  // in the opposite corner.
+  opposite_colors = {
+    white = "black"
+    red   = "blue"
+    black = "white"
+    blue  = "red"
+  }
+}
+
+// ===== BOARD ================================================================
+// Board size definition.
+board {
+  height = 16
+  width  = 16
+}
+
+// ===== PLAYERS ==============================================================
+// Players block lists the players in their turn order. Each player has
+// a color and a forward direction, which is the direction their pieces are
+// heading to.
+players {
+  player "white" {
+    forward_direction = [1, 1]
+  }
+  player "red" {
+    forward_direction = [1, -1]
+  }
+  player "black" {
+    forward_direction = [-1, -1]
+  }
+  player "blue" {
+    forward_direction = [-1, 1]
+  }
+}
+
+// ===== PIECE TYPES SPECIFICATION ============================================
+// Each piece type should specify the motions it is able to perform.
+//
+// Motions are specified by giving a generator function name, which generates
+// all possible destination squares given:
+//   * the current square of the piece,
+//   * the piece that is about to move.
+//
+// Motions can specify special action, such as pawn promotion in chess, that
+// can alter the game state after the motion is taken. It can be defined via
+// the attribute named "action", which points to a function receiving:
+//   * the piece that moved,
+//   * the starting square,
+//   * the destination square,
+//   * (optionally) user options.
+// Actions are not expected to produce any result, but can use builtin
+// functions to modify the game state.
+//
+// The last argument to an action function contains the user's decisions
+// regarding the current action. Choice tree, from which such decisions
+// can be made, is specified via another attribute of motion configuration --
+// "choice". This function receives arguments:
+//   * the piece that moved,
+//   * the starting square,
+//   * the destination square,
+// and is expected to return a choice tree object.
+//
+// Generator, action and choice functions are implemented below the piece types
+// definition.
+//
+// Piece appearance can be also configured via the "presentation" block inside
+// the piece type's definition.
+
+piece_types {
+  piece_type "piece" {
+    presentation {
+      white {
+        symbol = "○"
+        icon   = "/piece_types/disk.svg"
+      }
+      red {
+        symbol = "◇"
+        icon   = "/piece_types/disk.svg"
+      }
+      black {
+        symbol = "●"
+        icon   = "/piece_types/disk.svg"
+      }
+      blue {
+        symbol = "◆"
+        icon   = "/piece_types/disk.svg"
+      }
+    }
+    motion {
+      generator = "motion_neighbours"
+    }
+    motion {
+      generator = "motion_jump"
+    }
+  }
+}
+
+// ===== MOTION GENERATOR FUNCTIONS ===========================================
+// They receive 2 parameters:
+//  * square - the current square,
+//  * piece - the current piece,
+// and generate list of squares that the given piece can move to from the given
+// square.
+
+// Generates motions to all the 8 neighbours of the current square,
+// given that they are not occupied.
+composite_function "motion_neighbours" {
+  params = [square, piece]
+  result = {
+    dposes = [
+      [0, 1], [1, 0], [0, -1], [-1, 0],
+      [1, 1], [1, -1], [-1, 1], [-1, -1]
+    ]
+    dests = [for dpos in dposes : get_square_relative(square, dpos)]
+    return = [
+      for dest in dests : dest
+      if dest == null ? false : !is_occupied(dest)
+    ]
+  }
+}
+
+// Generates motions from current position by jumping over a piece in any
+// direction iteratively, until end of board or a blocking piece is encountered.
+composite_function "motion_jump" {
+  params = [square, piece]
+  result = {
+    return = motion_jump_step([], [square])
+  }
+}
+
+// Iteration step of motion jump.
+composite_function "motion_jump_step" {
+  params = [result, to_process]
+  result = {
+    curr_square = to_process[0]
+    dests       = motion_jump_step_impl(curr_square)
+    deduped_dests = [
+      for square in dests : square
+      if !contains(result, square)
+    ]
+    new_result = concat(result, deduped_dests)
+    new_to_process = (
+      length(to_process) == 1
+      ? deduped_dests
+      : concat(
+        slice(to_process, 1, length(to_process) - 1),
+        deduped_dests
+      )
+    )
+    next_step_result = cond_call(
+      length(new_to_process) > 0,
+      "motion_jump_step",
+      new_result,
+      new_to_process
+    )
+    return = (
+      next_step_result == null
+      ? new_result
+      : next_step_result
+    )
+  }
+}
+
+// Implementation of motion jump step.
+composite_function "motion_jump_step_impl" {
+  params = [square]
+  result = {
+    mid_dposes = [
+      [0, 1], [1, 0], [0, -1], [-1, 0],
+      [1, 1], [1, -1], [-1, 1], [-1, -1]
+    ]
+    dest_dposes = [for dpos in mid_dposes : [dpos[0] * 2, dpos[1] * 2]]
+    mids = [
+      for dpos in mid_dposes
+      : get_square_relative(square, dpos)
+    ]
+    valid_mid_idxs = [
+      for i, square in mids : i
+      if square == null ? false : is_occupied(square)
+    ]
+    dests = [
+      for i in valid_mid_idxs
+      : get_square_relative(square, dest_dposes[i])
+    ]
+    return = [
+      for square in dests : square
+      if square == null ? false : !is_occupied(square)
+    ]
+  }
+}
+
+// ===== HELPER FUNCTIONS =====================================================
+// Checks if square is occupied.
+function "is_occupied" {
+  params = [square]
+  result = piece_at(square) != null
+}
+
+// ===== GAME STATE VALIDATORS ================================================
+// Validators are called just after a move is taken. If any validator returns
+// false, then the move is reversed - it cannot be completed.
+//
+// Validators receive 1 parameter - the last move and return true if the state
+// is valid or false otherwise.
+
+// No state validators in Halma.
+
+// ===== INITIAL STATE ========================================================
+// Initial state block specifies the initial placement of all the pieces,
+// grouped by the players' colors.
+initial_state {
+  pieces = {
+    for color, camp in camps : color => { for pos in camp : pos => "piece" }
+  }
+}
+
+// ===== TURN =================================================================
+// Turn block designates a function which controls the flow of a single turn
+// (attribute "action"). Similarly to motion actions, turn action can interpret
+// player choices via a choice generator. Read piece_types description for a
+// more detailed description.
+turn {
+  choice = "turn_choose_move"
+  action = "turn"
+}
+
+function "turn_choose_move" {
+  params = []
+  result = { type = "move", message = "Choose move" }
+}
+
+composite_function "turn" {
+  params = [options]
+  result = {
+    _ = make_move(options[0].move, slice(options, 1, length(options)))
+  }
+}
+
+// ===== GAME RESOLVING FUNCTIONS =============================================
+// Namely the function "resolve" and its helpers
+
+// This function is called at the end of every turn.
+// Returns an object of type {did_end: bool, winner: color}. If did_end == true
+// and winner == null then draw is concluded.
+composite_function "resolve" {
+  params = [game]
+  result = {
+    winners = [for color in game.turn_order : color if did_win(color)]
+    return = (
+      length(winners) > 0
+      ? { did_end = true, winner = winners[0] }
+      : { did_end = false, winner = null }
+    )
+  }
+}
+
+// Checks if the given player occupies all of their target camp.
+composite_function "did_win" {
+  params = [color]
+  result = {
+    target_camp = camps[opposite_colors[color]]
+    pieces      = [for square in target_camp : piece_at(square)]
+    return = all([
+      for piece in pieces : piece == null ? false : piece.color == color
+    ]...)
+  }
+}
+
+// ===== ASSETS ===============================================================
+// Assets are additional files, which can be used in rules. These files are
+// copressed via gzip, and then ascii-encoded via base64. Any whitespace is
+// ignored.
+//
+// Assets can be organized into an arbitrally nested tree.
+assets = {
+  piece_types = {
+    "disk.svg" = <<EOF
+      H4sIAOFLWmUAA1WRXW7DIBCE33MKRF8SqcbgJK1D40TqTZCNHVoMaCF2fPtunLo/aKVdfZod0HA8
+      33pLBg3ReFdRwTgl2tW+Ma6r6DW1WUnPp9UxDh0ZjB7f/a2inHCy22NRgtsuVvSSUpB5Po4jG7fM
+      Q5cXnPMct+hpRcixI62xtqKDgnWWBasmDVntrYfnp7ZtN5TEBP5TLwofgnfapUWDZrNmsijxQdUm
+      TVK83U0zuFot9aCdb5oH+RU8XLPRNOkiBdsvwBqnaxUk+Ktr/sIPb9x/2pukwRpscrewRsWLAlCT
+      dPjMhf5cS0kC5WLroa9orxKY21o8Ez7X98Be+HyKbbkT5f71sJmTwqy0tSZETWpMuigYhlxPOAl2
+      oASQiRL7NPd8Djfv8IPuWZ9WX/hY10rPAQAA
+      EOF
+  }
+}
@@ -3,3 +3,4 @@ package integration
 const ChessRulesFile = "../rules/chess.hcl"
 const DobutsuShogiRulesFile = "../rules/dobutsu_shogi.hcl"
 const HalmaRulesFile = "../rules/halma.hcl"
+const HalmaFourPlayersRulesFile = "../rules/halma_4.hcl"
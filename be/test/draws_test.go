@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/mess"
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreefoldRepetition(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(ChessRulesFile, true)
+	require.NoError(t, err)
+
+	knightDance := []string{"G1-F3", "G8-F6", "F3-G1", "F6-G8"}
+
+	playRoutes(t, game, knightDance...)
+	assert.Equal(t, 2, game.RepetitionCount())
+	assert.False(t, game.Resolution().DidEnd)
+
+	playRoutes(t, game, knightDance...)
+	assert.Equal(t, 3, game.RepetitionCount())
+	resolution := game.Resolution()
+	assert.True(t, resolution.DidEnd)
+	assert.Nil(t, resolution.Winner)
+	assert.Equal(t, mess.Repetition, resolution.Reason)
+
+	err = game.RevertTurn()
+	require.NoError(t, err)
+	assert.Equal(t, 2, game.RepetitionCount())
+}
+
+func TestRepetitionRespectsCastlingRights(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(ChessRulesFile, true)
+	require.NoError(t, err)
+
+	playRoutes(t, game, "E2-E4", "E7-E5")
+	afterPawns := game.PositionKey()
+
+	playRoutes(t, game, "E1-E2", "E8-E7", "E2-E1", "E7-E8")
+	assert.NotEqual(t, afterPawns, game.PositionKey())
+	assert.Equal(t, 1, game.RepetitionCount())
+}
+
+func TestPliesSinceProgress(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(ChessRulesFile, true)
+	require.NoError(t, err)
+	pawn, err := game.GetPieceType("pawn")
+	require.NoError(t, err)
+
+	playRoutes(t, game, "E2-E4", "D7-D5", "E4-D5")
+	assert.Equal(t, 0, game.PliesSinceCapture())
+	assert.Equal(t, 0, game.PliesSinceMove(pawn))
+
+	playRoutes(t, game, "G8-F6", "G1-F3")
+	assert.Equal(t, 2, game.PliesSinceCapture())
+	assert.Equal(t, 2, game.PliesSinceMove(pawn))
+}
+
+func playRoutes(t *testing.T, game *mess.Game, routes ...string) {
+	t.Helper()
+	for _, routeStr := range routes {
+		route, err := notation.ParseRoute(game.State, routeStr)
+		require.NoError(t, err)
+		err = game.PlayTurn(route)
+		require.NoError(t, err, routeStr)
+	}
+}
@@ -385,4 +385,5 @@ func TestPromotionCheckMate(t *testing.T) {
 	assert.True(t, resolution.DidEnd)
 	t.Log(game.String())
 	assert.Equal(t, resolution.Winner, game.Player(color.White))
+	assert.Equal(t, mess.Checkmate, resolution.Reason)
 }
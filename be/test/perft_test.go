@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerftChess(t *testing.T) {
+	tests := []struct {
+		depth int
+		nodes int
+		long  bool
+	}{
+		{depth: 0, nodes: 1},
+		{depth: 1, nodes: 20},
+		{depth: 2, nodes: 400, long: true},
+	}
+	for _, tt := range tests {
+		if tt.long && testing.Short() {
+			continue
+		}
+		game, err := rules.DecodeRulesFromOs(ChessRulesFile, true)
+		require.NoError(t, err)
+
+		nodes, err := game.Perft(tt.depth)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.nodes, nodes, "depth %d", tt.depth)
+	}
+}
+
+func TestPerftDivideRestoresState(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(DobutsuShogiRulesFile, true)
+	require.NoError(t, err)
+	before := game.PrettyString()
+
+	divisions, err := game.PerftDivide(2)
+	require.NoError(t, err)
+	nodes, err := game.Perft(2)
+	require.NoError(t, err)
+
+	total := 0
+	for _, division := range divisions {
+		total += division.Nodes
+	}
+	assert.Len(t, divisions, 4)
+	assert.Equal(t, nodes, total)
+	assert.Equal(t, before, game.PrettyString())
+	assert.Equal(t, 0, game.TurnNumber())
+}
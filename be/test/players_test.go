@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/color"
+	"github.com/jostrzol/mess/pkg/notation"
+	"github.com/jostrzol/mess/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFourPlayersTurnOrder(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(HalmaFourPlayersRulesFile, true)
+	require.NoError(t, err)
+
+	turns := []struct {
+		color color.Color
+		route string
+	}{
+		{color.White, "D2-E3"},
+		{color.Red, "D15-E14"},
+		{color.Black, "M15-L14"},
+		{color.Blue, "M2-L3"},
+	}
+	for _, turn := range turns {
+		assert.Equal(t, turn.color, game.CurrentPlayer().Color())
+		route, err := notation.ParseRoute(game.State, turn.route)
+		require.NoError(t, err)
+		err = game.PlayTurn(route)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, color.White, game.CurrentPlayer().Color())
+	assert.Equal(t, 4, game.TurnNumber())
+	assert.False(t, game.Resolution().DidEnd)
+
+	err = game.RevertTurn()
+	require.NoError(t, err)
+	assert.Equal(t, color.Blue, game.CurrentPlayer().Color())
+}
+
+func TestFourPlayersForwardDirections(t *testing.T) {
+	game, err := rules.DecodeRulesFromOs(HalmaFourPlayersRulesFile, true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		color color.Color
+		x, y  int
+	}{
+		{color.White, 1, 1},
+		{color.Red, 1, -1},
+		{color.Black, -1, -1},
+		{color.Blue, -1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.color.String(), func(t *testing.T) {
+			player := game.Player(tt.color)
+			assert.Equal(t, tt.x, player.ForwardDirection().X)
+			assert.Equal(t, tt.y, player.ForwardDirection().Y)
+			assert.Len(t, player.Pieces(), 13)
+		})
+	}
+}
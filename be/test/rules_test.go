@@ -0,0 +1,16 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jostrzol/mess/pkg/rules/rulestest"
+)
+
+func TestRulesFiles(t *testing.T) {
+	for _, rulesFile := range []string{ChessRulesFile, DobutsuShogiRulesFile} {
+		t.Run(filepath.Base(rulesFile), func(t *testing.T) {
+			rulestest.RunAll(t, rulesFile)
+		})
+	}
+}
@@ -2,12 +2,14 @@ import { GameState } from "@/model/game/gameState";
 import { StaticData } from "@/model/game/gameStaticData";
 import { OptionNode, Route } from "@/model/game/options";
 import { Resolution } from "@/model/game/resolution";
+import { Turn } from "@/model/game/turn";
 import { UUID } from "crypto";
 import { MessApi } from "./messApi";
 import { GameStateDto, gameStateToModel } from "./schema/game";
 import { OptionNodeDto, optionNodeToModel, routeToDto } from "./schema/options";
 import { ResolutionDto, resolutionToModel } from "./schema/resoultion";
 import { StaticDataDto, staticDataToModel } from "./schema/staticData";
+import { TurnDto, turnToModel } from "./schema/turn";
 
 export class GameApi extends MessApi {
   public getStaticData = async (roomId: UUID): Promise<StaticData> => {
@@ -32,6 +34,29 @@ export class GameApi extends MessApi {
     return gameStateToModel(obj);
   };
 
+  public getStateAt = async (roomId: UUID, turn: number): Promise<GameState> => {
+    const res = await this.fetch("rooms/:id/game/state", {
+      method: "GET",
+      params: { id: roomId },
+      query: { turn },
+      credentials: "include",
+    });
+
+    const obj: GameStateDto = await res.json();
+    return gameStateToModel(obj);
+  };
+
+  public getTurns = async (roomId: UUID): Promise<Turn[]> => {
+    const res = await this.fetch("rooms/:id/game/turns", {
+      method: "GET",
+      params: { id: roomId },
+      credentials: "include",
+    });
+
+    const obj: TurnDto[] = await res.json();
+    return obj.map(turnToModel);
+  };
+
   public getTurnOptions = async (roomId: UUID): Promise<OptionNode | null> => {
     const res = await this.fetch("rooms/:id/game/options", {
       method: "GET",
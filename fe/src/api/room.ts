@@ -1,6 +1,8 @@
+import { ChatMessage } from "@/model/chat";
 import { Room } from "@/model/room";
 import { UUID } from "crypto";
 import { MessApi } from "./messApi";
+import { ChatMessageDto, chatMessageToModel } from "./schema/chat";
 import { RoomDto, roomToModel } from "./schema/room";
 
 export class RoomApi extends MessApi {
@@ -57,6 +59,44 @@ export class RoomApi extends MessApi {
     return roomToModel(obj);
   };
 
+  public setSetup = async (roomId: UUID, setup: string): Promise<Room> => {
+    const res = await this.fetch("rooms/:id/setup", {
+      method: "PUT",
+      params: { id: roomId },
+      credentials: "include",
+      body: JSON.stringify({ Setup: setup }),
+    });
+
+    const obj: RoomDto = await res.json();
+    return roomToModel(obj);
+  };
+
+  public getChat = async (roomId: UUID): Promise<ChatMessage[]> => {
+    const res = await this.fetch("rooms/:id/chat", {
+      method: "GET",
+      params: { id: roomId },
+      credentials: "include",
+    });
+
+    const obj: ChatMessageDto[] = await res.json();
+    return obj.map(chatMessageToModel);
+  };
+
+  public sendChatMessage = async (
+    roomId: UUID,
+    text: string,
+  ): Promise<ChatMessage> => {
+    const res = await this.fetch("rooms/:id/chat", {
+      method: "POST",
+      params: { id: roomId },
+      credentials: "include",
+      body: JSON.stringify({ Text: text }),
+    });
+
+    const obj: ChatMessageDto = await res.json();
+    return chatMessageToModel(obj);
+  };
+
   public saveRules = async (
     roomId: UUID,
     filename: string,
@@ -1,4 +1,5 @@
 import { MessApi } from "./messApi";
+import { DiagnosticDto } from "./schema/rules";
 
 export class RulesApi extends MessApi {
   public format = async (src: string): Promise<string> => {
@@ -10,4 +11,14 @@ export class RulesApi extends MessApi {
 
     return await res.text();
   };
+
+  public validate = async (src: string): Promise<DiagnosticDto[]> => {
+    const res = await this.fetch("/rules/validate", {
+      method: "PUT",
+      credentials: "include",
+      body: src,
+    });
+
+    return await res.json();
+  };
 }
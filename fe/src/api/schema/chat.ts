@@ -0,0 +1,17 @@
+import { ChatMessage } from "@/model/chat";
+
+export interface ChatMessageDto {
+  Seat: number;
+  IsMine: boolean;
+  Text: string;
+  Time: string;
+}
+
+export const chatMessageToModel = (message: ChatMessageDto): ChatMessage => {
+  return {
+    seat: message.Seat,
+    isMine: message.IsMine,
+    text: message.Text,
+    time: new Date(message.Time),
+  };
+};
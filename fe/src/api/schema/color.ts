@@ -1,6 +1,14 @@
 import { Color } from "@/model/game/color";
 
-export type ColorDto = "black" | "white";
+export type ColorDto =
+  | "white"
+  | "black"
+  | "red"
+  | "green"
+  | "blue"
+  | "yellow"
+  | "orange"
+  | "purple";
 
 export const colorToModel = (color: ColorDto): Color => {
   return color;
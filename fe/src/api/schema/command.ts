@@ -0,0 +1,40 @@
+import { UUID } from "crypto";
+import { RouteDto } from "./options";
+
+interface CommandBase {
+  RequestID: string;
+  RoomID?: UUID;
+}
+
+export interface PingCommand extends CommandBase {
+  CommandType: "Ping";
+}
+
+export interface RequestOptionsCommand extends CommandBase {
+  CommandType: "RequestOptions";
+  RoomID: UUID;
+}
+
+export interface PlayTurnCommand extends CommandBase {
+  CommandType: "PlayTurn";
+  RoomID: UUID;
+  Data: { Turn: number; Route: RouteDto };
+}
+
+export interface ChatCommand extends CommandBase {
+  CommandType: "Chat";
+  RoomID: UUID;
+  Data: { Text: string };
+}
+
+export type Command =
+  | PingCommand
+  | RequestOptionsCommand
+  | PlayTurnCommand
+  | ChatCommand;
+
+export interface ErrorDto {
+  Status: number;
+  Message: string;
+  Validation?: { Field: string; Message: string }[];
+}
@@ -1,9 +1,76 @@
-export interface RoomChanged {
+import { ChatMessageDto } from "./chat";
+import { ErrorDto } from "./command";
+import { GameStateDto } from "./game";
+import { ResolutionDto } from "./resoultion";
+import { RoomDto } from "./room";
+import { StaticDataDto } from "./staticData";
+import { TurnDto } from "./turn";
+
+export const EVENTS_VERSION = 1;
+
+interface EventBase {
+  Version: number;
+  IsAck?: boolean;
+}
+
+export interface RoomChanged extends EventBase {
   EventType: "RoomChanged";
+  Data: { Room: RoomDto };
+}
+
+export interface PlayerJoined extends EventBase {
+  EventType: "PlayerJoined";
+  Data: { Seat: number; IsComputer: boolean; Room: RoomDto };
+}
+
+export interface RulesChanged extends EventBase {
+  EventType: "RulesChanged";
+  Data: { Filename: string; Hash: string; Room: RoomDto };
+}
+
+export interface GameStarted extends EventBase {
+  EventType: "GameStarted";
+  Data: { StaticData: StaticDataDto };
 }
 
-export interface GameChanged {
+export interface TurnPlayed extends EventBase {
+  EventType: "TurnPlayed";
+  Data: { Turn: TurnDto };
+}
+
+export interface GameChanged extends EventBase {
   EventType: "GameChanged";
+  Data: { State: GameStateDto };
+}
+
+export interface GameEnded extends EventBase {
+  EventType: "GameEnded";
+  Data: { Resolution: ResolutionDto };
+}
+
+export interface ChatMessage extends EventBase {
+  EventType: "ChatMessage";
+  Data: ChatMessageDto;
+}
+
+export interface Reply extends EventBase {
+  EventType: "Reply";
+  Data: { RequestID: string; Result?: unknown };
+}
+
+export interface ErrorReply extends EventBase {
+  EventType: "Error";
+  Data: { RequestID: string; Error: ErrorDto };
 }
 
-export type Event = RoomChanged | GameChanged;
+export type Event =
+  | RoomChanged
+  | PlayerJoined
+  | RulesChanged
+  | GameStarted
+  | TurnPlayed
+  | GameChanged
+  | GameEnded
+  | ChatMessage
+  | Reply
+  | ErrorReply;
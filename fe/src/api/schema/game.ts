@@ -5,6 +5,7 @@ export interface GameStateDto {
   TurnNumber: number;
   Pieces: PieceDto[];
   IsMyTurn: boolean;
+  Setup?: string;
 }
 
 export const gameStateToModel = (state: GameStateDto): GameState => {
@@ -12,5 +13,6 @@ export const gameStateToModel = (state: GameStateDto): GameState => {
     turnNumber: state.TurnNumber,
     pieces: state.Pieces.map(pieceToModel),
     isMyTurn: state.IsMyTurn,
+    setup: state.Setup,
   };
 };
@@ -8,6 +8,7 @@ export interface RoomDto {
   IsStartable: boolean;
   IsStarted: boolean;
   RulesFilename: string;
+  Setup: string;
 }
 
 export const roomToModel = (room: RoomDto): Room => {
@@ -18,5 +19,6 @@ export const roomToModel = (room: RoomDto): Room => {
     isStartable: room.IsStartable,
     isStarted: room.IsStarted,
     rulesFilename: room.RulesFilename,
+    setup: room.Setup,
   };
 };
@@ -0,0 +1,17 @@
+export interface DiagnosticDto {
+  Severity: "error" | "warning";
+  Summary: string;
+  Detail: string;
+  Range?: RangeDto;
+}
+
+export interface RangeDto {
+  Start: PosDto;
+  End: PosDto;
+}
+
+export interface PosDto {
+  Line: number;
+  Column: number;
+  Byte: number;
+}
@@ -1,12 +1,19 @@
-import { Board } from "@/model/game/board";
+import { Board, Topology } from "@/model/game/board";
 import { StaticData } from "@/model/game/gameStaticData";
+import { Square } from "@/model/game/square";
 import { UUID } from "crypto";
 import { ColorDto, colorToModel } from "./color";
+import { SquareDto, squareToModel } from "./square";
+import { ZoneDto, zoneToModel } from "./zone";
 
 export interface StaticDataDto {
   ID: UUID;
   BoardSize: BoardSizeDto;
+  DisabledSquares: SquareDto[];
+  Topology: Topology;
   MyColor: ColorDto;
+  Colors: ColorDto[];
+  Zones: ZoneDto[];
 }
 
 export interface BoardSizeDto {
@@ -16,11 +23,25 @@ export interface BoardSizeDto {
 
 export const staticDataToModel = (staticData: StaticDataDto): StaticData => ({
   id: staticData.ID,
-  board: boardSizeToModel(staticData.BoardSize),
+  board: boardToModel(
+    staticData.BoardSize,
+    staticData.DisabledSquares,
+    staticData.Topology,
+  ),
   myColor: colorToModel(staticData.MyColor),
+  colors: staticData.Colors.map(colorToModel),
+  zones: staticData.Zones.map(zoneToModel),
 });
 
-const boardSizeToModel = (boardSize: BoardSizeDto): Board => ({
+const boardToModel = (
+  boardSize: BoardSizeDto,
+  disabledSquares: SquareDto[],
+  topology: Topology,
+): Board => ({
   height: boardSize.Height,
   width: boardSize.Width,
+  disabledSquares: disabledSquares.map((square) =>
+    Square.toString(squareToModel(square)),
+  ),
+  topology: topology,
 });
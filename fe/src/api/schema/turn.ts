@@ -0,0 +1,58 @@
+import { Turn, TurnEvent } from "@/model/game/turn";
+import { ColorDto, colorToModel } from "./color";
+import { SquareDto, squareToModel } from "./square";
+
+export interface TurnDto {
+  Number: number;
+  Color: ColorDto;
+  Route: string;
+  Events: TurnEventDto[];
+}
+
+export interface TurnEventDto {
+  Type: "PieceMoved" | "PiecePlaced" | "PieceRemoved" | "PieceCaptured";
+  PieceType: string;
+  Color: ColorDto;
+  From?: SquareDto;
+  To?: SquareDto;
+  Square?: SquareDto;
+  CapturedBy?: ColorDto;
+}
+
+export const turnToModel = (turn: TurnDto): Turn => {
+  return {
+    number: turn.Number,
+    color: colorToModel(turn.Color),
+    route: turn.Route,
+    events: turn.Events.map(turnEventToModel),
+  };
+};
+
+export const turnEventToModel = (event: TurnEventDto): TurnEvent => {
+  const piece = {
+    pieceType: event.PieceType,
+    color: colorToModel(event.Color),
+  };
+  switch (event.Type) {
+    case "PieceMoved":
+      return {
+        type: event.Type,
+        ...piece,
+        from: squareToModel(event.From!),
+        to: squareToModel(event.To!),
+      };
+    case "PiecePlaced":
+    case "PieceRemoved":
+      return {
+        type: event.Type,
+        ...piece,
+        square: squareToModel(event.Square!),
+      };
+    case "PieceCaptured":
+      return {
+        type: event.Type,
+        ...piece,
+        capturedBy: colorToModel(event.CapturedBy!),
+      };
+  }
+};
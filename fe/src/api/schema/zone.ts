@@ -0,0 +1,26 @@
+import { Color } from "@/model/game/color";
+import { Square } from "@/model/game/square";
+import { Zone } from "@/model/game/zone";
+import { ColorDto, colorToModel } from "./color";
+import { SquareDto, squareToModel } from "./square";
+
+export interface ZoneDto {
+  Name: string;
+  Squares: SquareDto[];
+  PlayerSquares: Partial<Record<ColorDto, SquareDto[]>>;
+}
+
+export const zoneToModel = (zone: ZoneDto): Zone => {
+  const playerSquares: Partial<Record<Color, string[]>> = {};
+  for (const [color, squares] of Object.entries(zone.PlayerSquares)) {
+    playerSquares[colorToModel(color as ColorDto)] = squaresToKeys(squares);
+  }
+  return {
+    name: zone.Name,
+    squares: squaresToKeys(zone.Squares),
+    playerSquares: playerSquares,
+  };
+};
+
+const squaresToKeys = (squares: SquareDto[]): string[] =>
+  squares.map((square) => Square.toString(squareToModel(square)));
@@ -1,7 +1,9 @@
 "use client";
 
 import { GameApi } from "@/api/game";
-import { GameChanged } from "@/api/schema/event";
+import { GameChanged, GameEnded, TurnPlayed } from "@/api/schema/event";
+import { gameStateToModel } from "@/api/schema/game";
+import { resolutionToModel } from "@/api/schema/resoultion";
 import { Board } from "@/components/game/board";
 import { OptionIndicator } from "@/components/game/optionIndicator";
 import { PieceTypePopup } from "@/components/game/pieceTypePopup";
@@ -64,13 +66,26 @@ const GamePage = ({ params }: RoomPageParams) => {
       client.invalidateQueries({ queryKey: keyDynamic });
     },
   });
-  useRoomWebsocket<GameChanged>({
-    type: "GameChanged",
+  useRoomWebsocket<TurnPlayed>({
+    type: "TurnPlayed",
     onEvent: () => {
       client.invalidateQueries({ queryKey: keyDynamic });
       client.resetQueries({ queryKey: keyOptions });
     },
   });
+  useRoomWebsocket<GameChanged>({
+    type: "GameChanged",
+    onEvent: ({ Data }) => {
+      client.setQueryData(keyState, gameStateToModel(Data.State));
+      client.resetQueries({ queryKey: keyOptions });
+    },
+  });
+  useRoomWebsocket<GameEnded>({
+    type: "GameEnded",
+    onEvent: ({ Data }) => {
+      client.setQueryData(keyResolution, resolutionToModel(Data.Resolution));
+    },
+  });
 
   if (staticData === undefined || state === undefined) {
     return null;
@@ -1,7 +1,13 @@
 "use client";
 
 import { RoomApi } from "@/api/room";
-import { RoomChanged } from "@/api/schema/event";
+import {
+  GameStarted,
+  PlayerJoined,
+  RoomChanged,
+  RulesChanged,
+} from "@/api/schema/event";
+import { roomToModel } from "@/api/schema/room";
 import { Button } from "@/components/form/button";
 import { Main } from "@/components/main";
 import { Navbar, NavbarSpacer } from "@/components/navbar";
@@ -28,8 +34,14 @@ const RoomPage = ({ params }: RoomPageParams) => {
     },
   });
 
-  useRoomWebsocket<RoomChanged>({
-    type: "RoomChanged",
+  const setRoom = ({ Data }: RoomChanged | PlayerJoined | RulesChanged) => {
+    client.setQueryData(["room", params.roomId], roomToModel(Data.Room));
+  };
+  useRoomWebsocket<RoomChanged>({ type: "RoomChanged", onEvent: setRoom });
+  useRoomWebsocket<PlayerJoined>({ type: "PlayerJoined", onEvent: setRoom });
+  useRoomWebsocket<RulesChanged>({ type: "RulesChanged", onEvent: setRoom });
+  useRoomWebsocket<GameStarted>({
+    type: "GameStarted",
     onEvent: () => {
       client.invalidateQueries({ queryKey: ["room", params.roomId] });
     },
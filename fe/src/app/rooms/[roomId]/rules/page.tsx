@@ -45,6 +45,7 @@ const RoomPage = ({ params: { roomId } }: RoomPageParams) => {
       client.setQueryData(["room", roomId], {
         ...room,
         rulesFilename: filename,
+        setup: "",
       });
     },
   });
@@ -22,7 +22,8 @@ export const Board = (props: BoardProps) => {
 };
 
 const BoardWrapped = ({ board }: BoardProps) => {
-  const gridTemplateColumns = `repeat(${board.width}, 1fr)`;
+  const halfColumns = BoardModel.halfColumns(board);
+  const gridTemplateColumns = `repeat(${halfColumns}, 1fr)`;
   const gridTemplateRows = `repeat(${board.height}, 1fr)`;
 
   const { pieceMap, isMyTurn } = useGameState();
@@ -72,7 +73,7 @@ const BoardWrapped = ({ board }: BoardProps) => {
           "justify-center",
           draggedPiece && ["cursor-none", "[&_*]:cursor-none"],
         )}
-        style={{ aspectRatio: `${board.width} / ${board.height}` }}
+        style={{ aspectRatio: `${halfColumns / 2} / ${board.height}` }}
       >
         <div
           className={clsx("grid", "grid-flow-row")}
@@ -82,11 +83,16 @@ const BoardWrapped = ({ board }: BoardProps) => {
           }
         >
           {BoardModel.MapSquares(board, (square, key) => {
+            const placement = BoardModel.gridPlacement(board, square);
+            if (BoardModel.isDisabled(board, key)) {
+              return <div key={key} style={placement} />;
+            }
             const piece = pieceMap[key];
             const squareRouteItem = squareMap[key];
             return (
               <Tile
                 key={key}
+                style={placement}
                 square={square}
                 isDot={destinations.includes(key)}
                 dotType={piece ? "danger" : "normal"}
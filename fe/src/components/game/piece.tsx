@@ -90,7 +90,7 @@ export const PieceIcon = ({
       <svg
         viewBox="0 0 100 100"
         className={clsx(
-          color == "white" ? "player-white" : "player-black",
+          `player-${color}`,
           "text-player",
           className,
         )}
@@ -116,7 +116,7 @@ export const PieceIcon = ({
     ) : (
       <ReactSVG
         className={clsx(
-          color == "white" ? "player-white" : "player-black",
+          `player-${color}`,
           "transition-transform",
           className,
         )}
@@ -19,13 +19,21 @@ export type RoomWebsocketContextValue = {
   getWebSocket: () => WebSocketLike | null;
 };
 
+// Acknowledgements of the user's own actions are skipped, unless requested
+// with onAck.
 export const useRoomWebsocket = <T extends Event>(handler?: {
   type: T["EventType"];
   onEvent: (event: T) => void;
+  onAck?: boolean;
 }) => {
   const ws = useContext(RoomWebsocketContext);
   useEffect(() => {
-    if (handler && ws.lastEvent?.EventType === handler.type) {
+    const isAck = ws.lastEvent?.IsAck ?? false;
+    if (
+      handler &&
+      ws.lastEvent?.EventType === handler.type &&
+      (!isAck || handler.onAck)
+    ) {
       handler.onEvent(ws.lastEvent as T);
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
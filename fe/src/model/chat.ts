@@ -0,0 +1,7 @@
+export interface ChatMessage {
+  // seat is the index of the author among the players, or -1 for spectators.
+  seat: number;
+  isMine: boolean;
+  text: string;
+  time: Date;
+}
@@ -3,8 +3,13 @@ import { Square } from "./square";
 export interface Board {
   height: number;
   width: number;
+  // keys of the squares, which are not a part of the board
+  disabledSquares: string[];
+  topology: Topology;
 }
 
+export type Topology = "rectangular" | "cylindrical" | "toroidal" | "hexagonal";
+
 export namespace Board {
   export const MapSquares = <T>(
     board: Board,
@@ -19,4 +24,26 @@ export namespace Board {
         return func(square, key);
       }),
     );
+
+  export const isDisabled = (board: Board, key: string): boolean =>
+    board.disabledSquares.includes(key);
+
+  // shifts each rank of a hexagonal board by half a cell to the right relative
+  // to the one above it; measured in halves of a cell
+  export const rowIndent = (board: Board, y: number): number =>
+    board.topology === "hexagonal" ? board.height - 1 - y : 0;
+
+  // number of half-cell columns fitting all the ranks with their indents
+  export const halfColumns = (board: Board): number =>
+    2 * board.width + rowIndent(board, 0);
+
+  // grid placement of the square, with the top rank in the first row and each
+  // square spanning two half-cell columns
+  export const gridPlacement = (
+    board: Board,
+    square: Square,
+  ): { gridRow: number; gridColumn: string } => ({
+    gridRow: board.height - square[1],
+    gridColumn: `${rowIndent(board, square[1]) + 2 * square[0] + 1} / span 2`,
+  });
 }
@@ -1 +1,9 @@
-export type Color = "white" | "black";
+export type Color =
+  | "white"
+  | "black"
+  | "red"
+  | "green"
+  | "blue"
+  | "yellow"
+  | "orange"
+  | "purple";
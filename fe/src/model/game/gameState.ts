@@ -4,4 +4,5 @@ export interface GameState {
   turnNumber: number;
   pieces: Piece[];
   isMyTurn: boolean;
+  setup?: string;
 }
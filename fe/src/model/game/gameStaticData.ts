@@ -1,9 +1,12 @@
 import { UUID } from "crypto";
 import { Board } from "./board";
 import { Color } from "./color";
+import { Zone } from "./zone";
 
 export interface StaticData {
   id: UUID;
   board: Board;
   myColor: Color;
+  colors: Color[];
+  zones: Zone[];
 }
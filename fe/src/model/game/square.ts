@@ -2,16 +2,15 @@ export type Square = [number, number];
 
 export namespace Square {
   const A_CODE = "A".charCodeAt(0);
-  const Z_CODE = "Z".charCodeAt(0);
-  const MAX_X = Z_CODE - A_CODE;
+  const LETTERS = 26;
   export const file = (square: Square): string => {
-    if (square[0] > MAX_X) {
-      throw Error(
-        `Coord x=${square[0]} too big to stringify. Maximum is ${MAX_X}`,
-      );
+    // A, B, ..., Z, AA, AB, ...
+    let result = "";
+    for (let x = square[0] + 1; x > 0; x = Math.floor(x / LETTERS)) {
+      x--;
+      result = String.fromCharCode(A_CODE + (x % LETTERS)) + result;
     }
-    const code = A_CODE + square[0];
-    return String.fromCharCode(code);
+    return result;
   };
 
   export const rank = (square: Square): string => {
@@ -0,0 +1,44 @@
+import { Color } from "./color";
+import { Square } from "./square";
+
+export interface Turn {
+  number: number;
+  color: Color;
+  route: string;
+  events: TurnEvent[];
+}
+
+export type TurnEvent =
+  | PieceMoved
+  | PiecePlaced
+  | PieceRemoved
+  | PieceCaptured;
+
+export interface PieceMoved {
+  type: "PieceMoved";
+  pieceType: string;
+  color: Color;
+  from: Square;
+  to: Square;
+}
+
+export interface PiecePlaced {
+  type: "PiecePlaced";
+  pieceType: string;
+  color: Color;
+  square: Square;
+}
+
+export interface PieceRemoved {
+  type: "PieceRemoved";
+  pieceType: string;
+  color: Color;
+  square: Square;
+}
+
+export interface PieceCaptured {
+  type: "PieceCaptured";
+  pieceType: string;
+  color: Color;
+  capturedBy: Color;
+}
@@ -0,0 +1,16 @@
+import { Color } from "./color";
+
+export interface Zone {
+  name: string;
+  // keys of the squares belonging to the zone for every player
+  squares: string[];
+  // keys of the squares belonging to the zone only for the given player
+  playerSquares: Partial<Record<Color, string[]>>;
+}
+
+export namespace Zone {
+  export const squaresFor = (zone: Zone, color: Color): string[] => [
+    ...zone.squares,
+    ...(zone.playerSquares[color] ?? []),
+  ];
+}
@@ -7,4 +7,5 @@ export interface Room {
   isStarted: boolean;
   isStartable: boolean;
   rulesFilename: string;
+  setup: string;
 }
@@ -8,6 +8,12 @@ export interface ThemeColors {
   "txt-dim": string;
   "player-white": string;
   "player-black": string;
+  "player-red": string;
+  "player-green": string;
+  "player-blue": string;
+  "player-yellow": string;
+  "player-orange": string;
+  "player-purple": string;
   danger: string;
   "danger-dim": string;
   warn: string;
@@ -34,6 +40,12 @@ export const themes = {
       "txt-dim": colors.slate[400],
       "player-white": colors.slate[50],
       "player-black": colors.slate[950],
+      "player-red": colors.red[600],
+      "player-green": colors.green[600],
+      "player-blue": colors.blue[600],
+      "player-yellow": colors.yellow[400],
+      "player-orange": colors.orange[500],
+      "player-purple": colors.purple[600],
       danger: colors.rose[600],
       "danger-dim": colors.rose[400],
       warn: colors.amber[500],
@@ -54,6 +66,12 @@ export const themes = {
       "txt-dim": colors.slate[600],
       "player-white": colors.slate[50],
       "player-black": colors.slate[950],
+      "player-red": colors.red[600],
+      "player-green": colors.green[600],
+      "player-blue": colors.blue[600],
+      "player-yellow": colors.yellow[400],
+      "player-orange": colors.orange[500],
+      "player-purple": colors.purple[600],
       danger: colors.rose[600],
       "danger-dim": colors.rose[800],
       warn: colors.amber[500],